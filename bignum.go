@@ -0,0 +1,207 @@
+package go_data_chain
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// ToBigInt returns the data as a *big.Int, a value that can not be converted returns 0
+func (m *Data) ToBigInt() *big.Int {
+	v, err := m.ToBigIntE()
+	m.addError(err)
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+// ToBigIntE returns the data as a *big.Int
+// strings and json.Number are read without passing through float64 so large values keep every digit,
+// fractions are truncated like the other integer conversions or return ErrPrecision in strict mode
+func (m *Data) ToBigIntE() (*big.Int, error) {
+	r, err := m.toRat("*big.Int")
+	if err != nil {
+		return nil, err
+	}
+	if !r.IsInt() && m.opts.numeric == NumericStrict {
+		return nil, m.conversionError("*big.Int", ErrPrecision)
+	}
+	//Quo truncates towards zero
+	return new(big.Int).Quo(r.Num(), r.Denom()), nil
+}
+
+// ToBigFloat returns the data as a *big.Float, a value that can not be converted returns 0
+func (m *Data) ToBigFloat() *big.Float {
+	v, err := m.ToBigFloatE()
+	m.addError(err)
+	if v == nil {
+		return new(big.Float)
+	}
+	return v
+}
+
+// ToBigFloatE returns the data as a *big.Float
+// the precision is at least 64 bits and large enough to hold the digits of the value
+func (m *Data) ToBigFloatE() (*big.Float, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	if f, ok := m.value.(*big.Float); ok && f != nil {
+		return new(big.Float).Copy(f), nil
+	}
+	r, err := m.toRat("*big.Float")
+	if err != nil {
+		return nil, err
+	}
+	prec := uint(r.Num().BitLen() + r.Denom().BitLen())
+	if prec < 64 {
+		prec = 64
+	}
+	return new(big.Float).SetPrec(prec).SetRat(r), nil
+}
+
+// ToBigRat returns the data as a *big.Rat, a value that can not be converted returns 0
+func (m *Data) ToBigRat() *big.Rat {
+	v, err := m.ToBigRatE()
+	m.addError(err)
+	if v == nil {
+		return new(big.Rat)
+	}
+	return v
+}
+
+// ToBigRatE returns the data as an exact *big.Rat
+// decimal strings and json.Number keep their exact value, floats keep their exact binary value
+func (m *Data) ToBigRatE() (*big.Rat, error) {
+	return m.toRat("*big.Rat")
+}
+
+// ToDecimalString returns the data as a decimal string without an exponent, a value that can not be converted returns ""
+func (m *Data) ToDecimalString() string {
+	v, err := m.ToDecimalStringE()
+	m.addError(err)
+	return v
+}
+
+// ToDecimalStringE returns the data as a decimal string without an exponent
+// strings, json.Number and integers are formatted exactly, e.g. "1.5e3" becomes "1000"
+// floats use the shortest string that reads back as the same float
+// a fraction that has no exact decimal form such as "1/3" returns ErrPrecision
+func (m *Data) ToDecimalStringE() (string, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	switch val := m.value.(type) {
+	case float32:
+		if math.IsNaN(float64(val)) || math.IsInf(float64(val), 0) {
+			return "", m.conversionError("decimal", ErrNotFinite)
+		}
+		return strconv.FormatFloat(float64(val), 'f', -1, 32), nil
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return "", m.conversionError("decimal", ErrNotFinite)
+		}
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case *big.Float:
+		if val != nil && !val.IsInf() {
+			return val.Text('f', -1), nil
+		}
+	}
+	r, err := m.toRat("decimal")
+	if err != nil {
+		return "", err
+	}
+	s, ok := formatDecimal(r)
+	if !ok {
+		return "", m.conversionError("decimal", ErrPrecision)
+	}
+	return s, nil
+}
+
+// toRat converts the value to an exact *big.Rat
+// - to: the name of the type for errors
+func (m *Data) toRat(to string) (*big.Rat, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	switch val := m.value.(type) {
+	case nil:
+		return nil, m.conversionError(to, fmt.Errorf("no value"))
+	case bool:
+		if val {
+			return big.NewRat(1, 1), nil
+		}
+		return new(big.Rat), nil
+	case *big.Int:
+		if val != nil {
+			return new(big.Rat).SetInt(val), nil
+		}
+	case *big.Rat:
+		if val != nil {
+			return new(big.Rat).Set(val), nil
+		}
+	case *big.Float:
+		if val != nil {
+			if val.IsInf() {
+				return nil, m.conversionError(to, ErrNotFinite)
+			}
+			r, _ := val.Rat(nil)
+			return r, nil
+		}
+	}
+	v := reflect.ValueOf(m.value)
+	switch v.Kind() {
+	case reflect.String:
+		//json.Number is a string so it never passes through float64
+		r, ok := new(big.Rat).SetString(v.String())
+		if !ok {
+			return nil, m.conversionError(to, strconv.ErrSyntax)
+		}
+		return r, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(v.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, m.conversionError(to, ErrNotFinite)
+		}
+		return new(big.Rat).SetFloat64(f), nil
+	}
+	return nil, m.conversionError(to, fmt.Errorf("unsupported type"))
+}
+
+// formatDecimal formats a rational number as an exact decimal string
+// returns false if the number has no exact decimal form
+func formatDecimal(r *big.Rat) (string, bool) {
+	if r.IsInt() {
+		return r.Num().String(), true
+	}
+	//a fraction ends if its denominator only has the factors 2 and 5
+	denom := new(big.Int).Set(r.Denom())
+	two, five, rem := big.NewInt(2), big.NewInt(5), new(big.Int)
+	twos, fives := 0, 0
+	for {
+		if q, _ := new(big.Int).QuoRem(denom, two, rem); rem.Sign() == 0 {
+			denom, twos = q, twos+1
+			continue
+		}
+		if q, _ := new(big.Int).QuoRem(denom, five, rem); rem.Sign() == 0 {
+			denom, fives = q, fives+1
+			continue
+		}
+		break
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return "", false
+	}
+	digits := twos
+	if fives > digits {
+		digits = fives
+	}
+	return r.FloatString(digits), true
+}
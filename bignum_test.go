@@ -0,0 +1,78 @@
+package go_data_chain
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestBigNumbers(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`{
+		"wei": 123456789012345678901234567890,
+		"amount": "0.10000000000000000001",
+		"price": 12.50,
+		"scientific": 1.5e3,
+		"fraction": 2.75,
+		"negative": -7.9
+	}`))
+	decoder.UseNumber()
+	var test_data interface{}
+	if err := decoder.Decode(&test_data); err != nil {
+		t.Fatal(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	tests := []struct {
+		name    string
+		key     string
+		bigInt  string
+		decimal string
+	}{
+		{name: "beyond_int64", key: "wei", bigInt: "123456789012345678901234567890", decimal: "123456789012345678901234567890"},
+		{name: "beyond_float64", key: "amount", bigInt: "0", decimal: "0.10000000000000000001"},
+		{name: "trailing_zero", key: "price", bigInt: "12", decimal: "12.5"},
+		{name: "exponent", key: "scientific", bigInt: "1500", decimal: "1500"},
+		{name: "truncate_negative", key: "negative", bigInt: "-7", decimal: "-7.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := chain.GetMapItem(tt.key)
+			if got := item.ToBigInt().String(); got != tt.bigInt {
+				t.Errorf("ToBigInt() = %v, want %v", got, tt.bigInt)
+			}
+			if got := item.ToDecimalString(); got != tt.decimal {
+				t.Errorf("ToDecimalString() = %v, want %v", got, tt.decimal)
+			}
+			//the exact value survives a round trip through big.Rat
+			want, _ := new(big.Rat).SetString(tt.decimal)
+			if got := item.ToBigRat(); got.Cmp(want) != 0 {
+				t.Errorf("ToBigRat() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	if got := chain.GetMapItem("wei").ToBigFloat().Text('f', 0); got != "123456789012345678901234567890" {
+		t.Errorf("ToBigFloat() = %v, want 123456789012345678901234567890", got)
+	}
+	if _, err := chain.GetMapItem("fraction").WithNumericMode(NumericStrict).ToBigIntE(); !errors.Is(err, ErrPrecision) {
+		t.Errorf("ToBigIntE() error = %v, want ErrPrecision", err)
+	}
+	if _, err := CreateDataChain("1/3", false).ToDecimalStringE(); !errors.Is(err, ErrPrecision) {
+		t.Errorf("ToDecimalStringE() error = %v, want ErrPrecision", err)
+	}
+	if _, err := CreateDataChain("abc", false).ToBigIntE(); err == nil {
+		t.Errorf("ToBigIntE() of abc should fail")
+	}
+	//the lenient methods add their errors to a safe chain like ToDuration
+	safe := CreateDataChain(map[string]interface{}{"bad": "abc"}, true)
+	if safe.GetMapItem("bad").ToBigInt().Sign() != 0 || safe.GetMapItem("bad").ToDecimalString() != "" || safe.Err == nil || len(safe.Err.(*MultiError).Errors) != 2 {
+		t.Errorf("Err = %v, want an error for ToBigInt and ToDecimalString", safe.Err)
+	}
+	if got := CreateDataChain(0.1, false).ToDecimalString(); got != "0.1" {
+		t.Errorf("ToDecimalString() = %v, want 0.1", got)
+	}
+	if got := CreateDataChain(uint64(18446744073709551615), false).ToBigInt().String(); got != "18446744073709551615" {
+		t.Errorf("ToBigInt() = %v, want 18446744073709551615", got)
+	}
+}
@@ -0,0 +1,492 @@
+package go_data_chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// asMap returns the value as a map if it is one, use mapItem to read a single key
+// other go maps such as the map[interface{}]interface{} from yaml.v2 and structs are read
+// through reflection into a new map with the keys converted to strings,
+// their values are not copied so changes to the returned map are not seen by the value
+func asMap(value interface{}) (map[string]interface{}, bool) {
+	if items, ok := value.(map[string]interface{}); ok {
+		return items, true
+	}
+	v := indirect(reflect.ValueOf(value))
+	switch v.Kind() {
+	case reflect.Map:
+		items := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			items[keyString(iter.Key())] = elemValue(iter.Value())
+		}
+		return items, true
+	case reflect.Struct:
+		return structItems(v)
+	}
+	return nil, false
+}
+
+// mapItem reads a single item of a map without copying the map the way asMap does
+// returns the item, true if the key exists and true if the value is a map
+func mapItem(value interface{}, key string) (interface{}, bool, bool) {
+	if items, ok := value.(map[string]interface{}); ok {
+		o, exists := items[key]
+		return o, exists, true
+	}
+	v := indirect(reflect.ValueOf(value))
+	switch v.Kind() {
+	case reflect.Map:
+		k, exists := mapKey(v, key)
+		if !exists {
+			return nil, false, true
+		}
+		return elemValue(v.MapIndex(k)), true, true
+	case reflect.Struct:
+		return structItem(v, key)
+	}
+	return nil, false, false
+}
+
+// asArray returns the value as an array if it is one
+// go slices and arrays of any type are read through reflection into a new array
+func asArray(value interface{}) ([]interface{}, bool) {
+	if items, ok := value.([]interface{}); ok {
+		return items, true
+	}
+	v := indirect(reflect.ValueOf(value))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = elemValue(v.Index(i))
+	}
+	return items, true
+}
+
+// nativeMap returns the value if it is a map[string]interface{} that can be changed in place
+func nativeMap(value interface{}) (map[string]interface{}, bool) {
+	items, ok := value.(map[string]interface{})
+	return items, ok
+}
+
+// nativeArray returns the value if it is an []interface{} that can be changed in place
+func nativeArray(value interface{}) ([]interface{}, bool) {
+	items, ok := value.([]interface{})
+	return items, ok
+}
+
+// keyString converts a go map key to the string used to look it up
+func keyString(key reflect.Value) string {
+	key = indirect(key)
+	if !key.IsValid() {
+		return "<nil>"
+	}
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+	return fmt.Sprint(key.Interface())
+}
+
+// mapKey finds the key of a go map that converts to the string key
+func mapKey(v reflect.Value, key string) (reflect.Value, bool) {
+	if v.Type().Key().Kind() == reflect.String {
+		k := reflect.ValueOf(key).Convert(v.Type().Key())
+		return k, v.MapIndex(k).IsValid()
+	}
+	if k := reflect.ValueOf(key); k.Type().AssignableTo(v.Type().Key()) && v.MapIndex(k).IsValid() {
+		//string keys of a map[interface{}]interface{} are found without a scan
+		return k, true
+	}
+	iter := v.MapRange()
+	for iter.Next() {
+		if keyString(iter.Key()) == key {
+			return iter.Key(), true
+		}
+	}
+	if v.Type().Key().Kind() == reflect.Interface {
+		return reflect.ValueOf(key), false
+	}
+	return reflect.Value{}, false
+}
+
+// setMapEntry sets an item in any go map that can hold the value
+// returns false if the value is not a map or the key or value do not fit its types
+func setMapEntry(container interface{}, key string, value interface{}) bool {
+	if items, ok := nativeMap(container); ok {
+		items[key] = value
+		return true
+	}
+	v := reflect.ValueOf(container)
+	if v.Kind() != reflect.Map || v.IsNil() {
+		return false
+	}
+	k, _ := mapKey(v, key)
+	o, ok := assignable(value, v.Type().Elem())
+	if !k.IsValid() || !ok {
+		return false
+	}
+	v.SetMapIndex(k, o)
+	return true
+}
+
+// replaceMapEntries replaces every item of any go map in place with the items of a map of the same type
+// returns false if the values are not maps of the same type
+func replaceMapEntries(container interface{}, items interface{}) bool {
+	v, o := reflect.ValueOf(container), reflect.ValueOf(items)
+	if v.Kind() != reflect.Map || v.IsNil() || !o.IsValid() || o.Type() != v.Type() {
+		return false
+	}
+	if v.Pointer() == o.Pointer() {
+		//the map was not changed
+		return true
+	}
+	for _, key := range v.MapKeys() {
+		v.SetMapIndex(key, reflect.Value{})
+	}
+	iter := o.MapRange()
+	for iter.Next() {
+		v.SetMapIndex(iter.Key(), iter.Value())
+	}
+	return true
+}
+
+// shallowCopy returns a copy of a map or slice with the same go type so the original is left untouched
+// the items are not copied, structs and arrays are copied into a new map or array of their items
+func shallowCopy(container interface{}) interface{} {
+	if items, ok := nativeMap(container); ok {
+		out := make(map[string]interface{}, len(items))
+		for k, o := range items {
+			out[k] = o
+		}
+		return out
+	}
+	if items, ok := nativeArray(container); ok {
+		return append([]interface{}{}, items...)
+	}
+	v := reflect.ValueOf(container)
+	switch v.Kind() {
+	case reflect.Map:
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return out.Interface()
+	case reflect.Slice:
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(out, v)
+		return out.Interface()
+	}
+	if items, ok := asMap(container); ok {
+		return items
+	}
+	if items, ok := asArray(container); ok {
+		return items
+	}
+	return container
+}
+
+// deleteMapEntry removes an item from any go map
+// returns false if the value is not a map or the key does not exist
+func deleteMapEntry(container interface{}, key string) bool {
+	if items, ok := nativeMap(container); ok {
+		if _, ok := items[key]; !ok {
+			return false
+		}
+		delete(items, key)
+		return true
+	}
+	v := reflect.ValueOf(container)
+	if v.Kind() != reflect.Map {
+		return false
+	}
+	k, found := mapKey(v, key)
+	if !found {
+		return false
+	}
+	v.SetMapIndex(k, reflect.Value{})
+	return true
+}
+
+// setArrayEntry replaces an item in any go slice that can hold the value
+// returns false if the value is not a slice, the index is out of range or the value does not fit its type
+func setArrayEntry(container interface{}, index int, value interface{}) bool {
+	if items, ok := nativeArray(container); ok {
+		if index < 0 || index >= len(items) {
+			return false
+		}
+		items[index] = value
+		return true
+	}
+	v := reflect.ValueOf(container)
+	if v.Kind() != reflect.Slice || index < 0 || index >= v.Len() {
+		return false
+	}
+	o, ok := assignable(value, v.Type().Elem())
+	if !ok {
+		return false
+	}
+	v.Index(index).Set(o)
+	return true
+}
+
+// insertArrayEntries returns any go slice with the values added before the index
+// a typed slice is copied into a new slice of the same type
+// - container: the slice
+// - index: the position of the first new item, the length of the slice appends them
+// - values: the values to add
+func insertArrayEntries(container interface{}, index int, values []interface{}) (interface{}, error) {
+	if items, ok := nativeArray(container); ok {
+		out := make([]interface{}, 0, len(items)+len(values))
+		out = append(out, items[:index]...)
+		out = append(out, values...)
+		return append(out, items[index:]...), nil
+	}
+	v := reflect.ValueOf(container)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can not grow a `%T`", container)
+	}
+	out := reflect.MakeSlice(v.Type(), 0, v.Len()+len(values))
+	out = reflect.AppendSlice(out, v.Slice(0, index))
+	for _, value := range values {
+		o, ok := assignable(value, v.Type().Elem())
+		if !ok {
+			return nil, fmt.Errorf("can not add `%T` to a `%T`", value, container)
+		}
+		out = reflect.Append(out, o)
+	}
+	return reflect.AppendSlice(out, v.Slice(index, v.Len())).Interface(), nil
+}
+
+// removeArrayEntry returns any go slice without the item at the index
+// a typed slice is copied into a new slice of the same type
+func removeArrayEntry(container interface{}, index int) (interface{}, error) {
+	if items, ok := nativeArray(container); ok {
+		return append(items[:index], items[index+1:]...), nil
+	}
+	v := reflect.ValueOf(container)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can not shrink a `%T`", container)
+	}
+	out := reflect.MakeSlice(v.Type(), 0, v.Len()-1)
+	out = reflect.AppendSlice(out, v.Slice(0, index))
+	return reflect.AppendSlice(out, v.Slice(index+1, v.Len())).Interface(), nil
+}
+
+// assignable returns the value as a reflect.Value that can be stored in the type
+func assignable(value interface{}, t reflect.Type) (reflect.Value, bool) {
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Map, reflect.Slice, reflect.Ptr:
+			return reflect.Zero(t), true
+		}
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(value)
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// indirect follows pointers and interfaces to the value they hold
+// a nil pointer returns the zero reflect.Value
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// elemValue returns the value of a map item, array item or struct field
+// pointers are followed so the chain sees the value they point to
+func elemValue(v reflect.Value) interface{} {
+	v = indirect(v)
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// structItems returns the exported fields of a struct as a map keyed the same way Decode matches them
+// fields of embedded structs are promoted unless the outer struct has a field with the same key
+// structs without exported fields such as time.Time are not maps
+func structItems(v reflect.Value) (map[string]interface{}, bool) {
+	t := v.Type()
+	items := map[string]interface{}{}
+	var embedded []reflect.Value
+	found := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, tagged := fieldName(field)
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && !tagged {
+			if fv := indirect(v.Field(i)); fv.Kind() == reflect.Struct {
+				embedded = append(embedded, fv)
+				found = true
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			//unexported
+			continue
+		}
+		items[name] = elemValue(v.Field(i))
+		found = true
+	}
+	for _, fv := range embedded {
+		inner, _ := structItems(fv)
+		for k, o := range inner {
+			if _, ok := items[k]; !ok {
+				items[k] = o
+			}
+		}
+	}
+	return items, found
+}
+
+// structItem reads a single field of a struct by the key structItems would give it
+// returns the value, true if the key exists and true if the struct is read as a map
+func structItem(v reflect.Value, key string) (interface{}, bool, bool) {
+	t := v.Type()
+	var embedded []reflect.Value
+	found := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, tagged := fieldName(field)
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && !tagged {
+			if fv := indirect(v.Field(i)); fv.Kind() == reflect.Struct {
+				embedded = append(embedded, fv)
+				found = true
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			//unexported
+			continue
+		}
+		if name == key {
+			return elemValue(v.Field(i)), true, true
+		}
+		found = true
+	}
+	//fields of the outer struct have already been checked so promoted fields can not hide them
+	for _, fv := range embedded {
+		if o, exists, _ := structItem(fv, key); exists {
+			return o, true, true
+		}
+	}
+	return nil, false, found
+}
+
+// fieldName returns the key of a struct field and if it came from a tag
+// the `chain` tag is used first, then the `json` and `yaml` tags, then the field name
+func fieldName(field reflect.StructField) (string, bool) {
+	for _, tag := range []string{"chain", "json", "yaml"} {
+		if v, ok := field.Tag.Lookup(tag); ok {
+			name := strings.Split(v, ",")[0]
+			if name != "" {
+				return name, true
+			}
+		}
+	}
+	return field.Name, false
+}
+
+// sortedKeys returns the keys of a map in a stable order
+func sortedKeys(items map[string]interface{}) []string {
+	keys := make([]string, 0, len(items))
+	for k := range items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// asNumber returns the value as a float64 if it is any of the go numeric types
+func asNumber(value interface{}) (float64, bool) {
+	switch val := value.(type) {
+	case json.Number:
+		f, err := val.Float64()
+		return f, err == nil
+	case nil:
+		return 0, false
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// deepEqual compares two values treating all numeric types by value
+func deepEqual(a interface{}, b interface{}) bool {
+	if fa, ok := asNumber(a); ok {
+		fb, ok := asNumber(b)
+		return ok && fa == fb
+	}
+	if items_a, ok := asArray(a); ok {
+		items_b, ok := asArray(b)
+		if !ok || len(items_a) != len(items_b) {
+			return false
+		}
+		for i := range items_a {
+			if !deepEqual(items_a[i], items_b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if items_a, ok := asMap(a); ok {
+		items_b, ok := asMap(b)
+		if !ok || len(items_a) != len(items_b) {
+			return false
+		}
+		for k, v := range items_a {
+			o, ok := items_b[k]
+			if !ok || !deepEqual(v, o) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// deepCopy copies maps and arrays recursively, other values are returned as they are
+func deepCopy(value interface{}) interface{} {
+	if items, ok := asMap(value); ok {
+		out := make(map[string]interface{}, len(items))
+		for k, o := range items {
+			out[k] = deepCopy(o)
+		}
+		return out
+	}
+	if items, ok := asArray(value); ok {
+		out := make([]interface{}, len(items))
+		for i, o := range items {
+			out[i] = deepCopy(o)
+		}
+		return out
+	}
+	return value
+}
@@ -0,0 +1,201 @@
+package go_data_chain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type collectionsMeta struct {
+	Owner string `yaml:"owner"`
+}
+
+type collectionsPort struct {
+	Name   string `json:"name"`
+	Number int    `json:"number"`
+}
+
+type collectionsService struct {
+	collectionsMeta
+	Name     string            `chain:"name"`
+	Replicas *int              `chain:"replicas"`
+	Ports    []collectionsPort `chain:"ports"`
+	Labels   map[string]string `chain:"labels"`
+	Weights  [2]float64        `chain:"weights"`
+	Config   interface{}       `chain:"config"`
+	Created  time.Time         `chain:"created"`
+	Secret   string            `chain:"-"`
+	internal string
+}
+
+func TestReflectNavigation(t *testing.T) {
+	replicas := 3
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	service := &collectionsService{
+		collectionsMeta: collectionsMeta{Owner: "ops"},
+		Name:            "web",
+		Replicas:        &replicas,
+		Ports:           []collectionsPort{{Name: "http", Number: 80}, {Name: "https", Number: 443}},
+		Labels:          map[string]string{"tier": "frontend"},
+		Weights:         [2]float64{0.25, 0.75},
+		Config:          map[string]interface{}{"debug": "yes"},
+		Created:         created,
+		Secret:          "hidden",
+		internal:        "hidden",
+	}
+	chain := CreateDataChain(service, false)
+	tests := []struct {
+		name string
+		args string
+		want interface{}
+	}{
+		{name: "field_by_tag", args: "name", want: "web"},
+		{name: "pointer_field", args: "replicas", want: 3},
+		{name: "embedded_field", args: "owner", want: "ops"},
+		{name: "typed_slice_of_structs", args: "ports[1].number", want: 443},
+		{name: "typed_map", args: "labels.tier", want: "frontend"},
+		{name: "go_array", args: "weights[1]", want: 0.75},
+		{name: "interface_field", args: "config.debug", want: "yes"},
+		{name: "struct_without_fields", args: "created", want: created},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chain.Get(tt.args).ToInterface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := chain.GetMapItem("ports").GetArrayCount(); got != 2 {
+		t.Errorf("GetArrayCount() = %v, want 2", got)
+	}
+	if got := chain.Get("config.debug").ToBool(); !got {
+		t.Errorf("ToBool() = %v, want true", got)
+	}
+	if got := len(chain.ToMap()); got != 8 {
+		t.Errorf("ToMap() has %v keys, want 8 without the hidden fields", got)
+	}
+	if got := chain.GetMapItem("labels").ToMap()["tier"]; got.ToString() != "frontend" {
+		t.Errorf("ToMap() = %v, want frontend", got.ToString())
+	}
+	if got := CreateDataChain([]string{"a", "b"}, false).ToArray(); len(got) != 2 || got[1].ToString() != "b" {
+		t.Errorf("ToArray() = %v, want [a b]", got)
+	}
+	if got := chain.Query("$.ports[?@.number > 100].name"); len(got) != 1 || got[0].ToString() != "https" {
+		t.Errorf("Query() = %v, want [https]", got)
+	}
+	if got := chain.Jq("[.ports[].number] | add").ToInt(); got != 523 {
+		t.Errorf("Jq() = %v, want 523", got)
+	}
+	//typed maps can only hold values of their own type
+	if err := chain.GetMapItem("labels").SetMapItem("tier", "backend"); err != nil || service.Labels["tier"] != "backend" {
+		t.Errorf("SetMapItem() = %v, labels = %v, want tier backend", err, service.Labels)
+	}
+	if err := chain.GetMapItem("labels").SetMapItem("tier", 1); err == nil {
+		t.Errorf("SetMapItem() of an int into a map[string]string should fail")
+	}
+}
+
+func TestReflectSafeMode(t *testing.T) {
+	chain := CreateDataChain(map[string]int{"a": 1}, true)
+	if got := chain.GetMapItem("a").ToInt(); got != 1 {
+		t.Errorf("GetMapItem() = %v, want 1", got)
+	}
+	chain.GetMapItem("b")
+	chain.GetArrayItem(0)
+	if chain.Err == nil {
+		t.Errorf("Err = nil, want the missing key and the not an array errors")
+	}
+}
+
+func TestOtherShapes(t *testing.T) {
+	//the shapes yaml.v2 and other decoders produce
+	data := map[interface{}]interface{}{
+		"name": "web",
+		1:      "one",
+		"ports": []map[string]interface{}{
+			{"name": "http", "number": 80},
+		},
+		"labels": map[string]string{"tier": "frontend"},
+		"nested": map[interface{}]interface{}{"enabled": true},
+	}
+	chain := CreateDataChain(data, false)
+	tests := []struct {
+		name string
+		args string
+		want interface{}
+	}{
+		{name: "interface_key", args: "name", want: "web"},
+		{name: "int_key", args: "1", want: "one"},
+		{name: "slice_of_maps", args: "ports[0].number", want: 80},
+		{name: "map_of_strings", args: "labels.tier", want: "frontend"},
+		{name: "nested_interface_map", args: "nested.enabled", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chain.Get(tt.args).ToInterface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := len(chain.ToMap()); got != 5 {
+		t.Errorf("ToMap() has %v keys, want 5", got)
+	}
+	if got := chain.GetMapItem("ports").GetArrayCount(); got != 1 {
+		t.Errorf("GetArrayCount() = %v, want 1", got)
+	}
+	if got := chain.GetMapItem("ports").ToArray(); len(got) != 1 || got[0].GetMapItem("name").ToString() != "http" {
+		t.Errorf("ToArray() = %v, want the http port", got)
+	}
+	//the original value is kept
+	if _, ok := chain.GetMapItem("nested").ToInterface().(map[interface{}]interface{}); !ok {
+		t.Errorf("ToInterface() = %T, want map[interface{}]interface{}", chain.GetMapItem("nested").ToInterface())
+	}
+	//changes are written back with the original key
+	if err := chain.Set("nested.enabled", "no"); err != nil || data["nested"].(map[interface{}]interface{})["enabled"] != "no" {
+		t.Errorf("Set() = %v, want enabled no", err)
+	}
+	if err := chain.GetMapItem("1").SetMapItem("x", 1); err == nil {
+		t.Errorf("SetMapItem() on a string should fail")
+	}
+	if err := chain.GetMapItem("nested").SetMapItem("enabled", false); err != nil || data["nested"].(map[interface{}]interface{})["enabled"] != false {
+		t.Errorf("SetMapItem() = %v, want enabled false", err)
+	}
+	if err := chain.GetMapItem("1").setValue("uno"); err != nil || data[1] != "uno" {
+		t.Errorf("setValue() = %v, data = %v, want the int key to be replaced", err, data)
+	}
+	if err := chain.DeleteMapItem("1"); err != nil || len(data) != 4 {
+		t.Errorf("DeleteMapItem() = %v, data = %v, want the int key removed", err, data)
+	}
+}
+
+func TestMapItem(t *testing.T) {
+	type inner struct {
+		Zone string `json:"zone"`
+		Name string
+	}
+	type outer struct {
+		inner
+		Name   string `chain:"name"`
+		hidden int
+	}
+	values := []interface{}{
+		map[string]interface{}{"name": "web", "empty": nil},
+		map[interface{}]interface{}{"name": "web", 1: "one"},
+		map[string]string{"name": "web"},
+		outer{inner: inner{Zone: "eu", Name: "inner"}, Name: "web"},
+		&outer{Name: "web"},
+		time.Time{},
+		"text",
+	}
+	for _, value := range values {
+		items, is_map := asMap(value)
+		for _, key := range []string{"name", "zone", "Name", "empty", "1", "hidden", "other"} {
+			want, want_exists := items[key]
+			got, exists, got_is_map := mapItem(value, key)
+			if got_is_map != is_map || exists != want_exists || !reflect.DeepEqual(got, want) {
+				t.Errorf("mapItem(%T, %v) = %v, %v, %v, want %v, %v, %v", value, key, got, exists, got_is_map, want, want_exists, is_map)
+			}
+		}
+	}
+}
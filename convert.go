@@ -0,0 +1,482 @@
+package go_data_chain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConversionError is returned by the ToXxxE methods when a value can not be converted
+type ConversionError struct {
+	// Path is the RFC 6901 JSON Pointer of the value
+	Path string
+	// From is the go type of the value
+	From  string
+	To    string
+	Value interface{}
+	Err   error
+}
+
+// Error returns the error as a string
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("can not convert `%v` (%s) at `%s` to %s: %v", e.Value, e.From, e.Path, e.To, e.Err)
+}
+
+// Unwrap returns the reason the value could not be converted
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// conversionError creates a ConversionError for the value of the data
+// - to: the name of the type the value was being converted to
+// - err: the reason
+func (m *Data) conversionError(to string, err error) error {
+	if m == nil {
+		m = m.missingItem()
+	}
+	from := "nil"
+	if m.value != nil {
+		from = reflect.TypeOf(m.value).String()
+	}
+	return &ConversionError{Path: m.JSONPointer(), From: from, To: to, Value: m.value, Err: err}
+}
+
+// ToStringE returns the data as a string
+// numbers and bools are formatted, nil, maps and arrays return an error
+func (m *Data) ToStringE() (string, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	if m.value == nil {
+		return "", m.conversionError("string", fmt.Errorf("no value"))
+	}
+	if s, ok := m.value.(string); ok {
+		return s, nil
+	}
+	if _, ok := asMap(m.value); ok {
+		return "", m.conversionError("string", fmt.Errorf("value is a map"))
+	}
+	if _, ok := asArray(m.value); ok {
+		return "", m.conversionError("string", fmt.Errorf("value is an array"))
+	}
+	return m.ToString(), nil
+}
+
+// ToIntE returns the data as an int
+func (m *Data) ToIntE() (int, error) {
+	i, err := m.toInt64E("int", strconv.IntSize)
+	return int(i), err
+}
+
+// ToInt8E returns the data as an int8
+func (m *Data) ToInt8E() (int8, error) {
+	i, err := m.toInt64E("int8", 8)
+	return int8(i), err
+}
+
+// ToInt16E returns the data as an int16
+func (m *Data) ToInt16E() (int16, error) {
+	i, err := m.toInt64E("int16", 16)
+	return int16(i), err
+}
+
+// ToInt32E returns the data as an int32
+func (m *Data) ToInt32E() (int32, error) {
+	i, err := m.toInt64E("int32", 32)
+	return int32(i), err
+}
+
+// ToInt64E returns the data as an int64
+func (m *Data) ToInt64E() (int64, error) {
+	return m.toInt64E("int64", 64)
+}
+
+// ToUintE returns the data as a uint
+func (m *Data) ToUintE() (uint, error) {
+	u, err := m.toUint64E("uint", strconv.IntSize)
+	return uint(u), err
+}
+
+// ToUint8E returns the data as a uint8
+func (m *Data) ToUint8E() (uint8, error) {
+	u, err := m.toUint64E("uint8", 8)
+	return uint8(u), err
+}
+
+// ToUint16E returns the data as a uint16
+func (m *Data) ToUint16E() (uint16, error) {
+	u, err := m.toUint64E("uint16", 16)
+	return uint16(u), err
+}
+
+// ToUint32E returns the data as a uint32
+func (m *Data) ToUint32E() (uint32, error) {
+	u, err := m.toUint64E("uint32", 32)
+	return uint32(u), err
+}
+
+// ToUint64E returns the data as a uint64
+func (m *Data) ToUint64E() (uint64, error) {
+	return m.toUint64E("uint64", 64)
+}
+
+// ToFloat32E returns the data as a float32
+func (m *Data) ToFloat32E() (float32, error) {
+	f, err := m.toFloat64E("float32", 32)
+	return float32(f), err
+}
+
+// ToFloat64E returns the data as a float64
+func (m *Data) ToFloat64E() (float64, error) {
+	return m.toFloat64E("float64", 64)
+}
+
+// ToBoolE returns the data as a bool
+// strings are matched the same way as ToBool, anything else returns an error
+func (m *Data) ToBoolE() (bool, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	switch val := m.value.(type) {
+	case nil:
+		return false, m.conversionError("bool", fmt.Errorf("no value"))
+	case bool:
+		return val, nil
+	case string:
+		switch strings.ToLower(val) {
+		case "t", "yes", "y", "1", "pass":
+			return true, nil
+		case "f", "no", "n", "0", "fail":
+			return false, nil
+		}
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return false, m.conversionError("bool", err)
+		}
+		return b, nil
+	}
+	if f, ok := asNumber(m.value); ok {
+		return f > 0, nil
+	}
+	return false, m.conversionError("bool", fmt.Errorf("unsupported type"))
+}
+
+// toInt64E converts the value to a signed integer of the size using the numeric mode
+// - to: the name of the type for errors
+// - bits: the size of the target type
+func (m *Data) toInt64E(to string, bits int) (int64, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	switch val := m.value.(type) {
+	case nil:
+		return 0, m.conversionError(to, fmt.Errorf("no value"))
+	case bool:
+		if val {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return m.fitInt(to, i, bits)
+		}
+		//large or fractional numbers
+		f, err := val.Float64()
+		if err != nil {
+			return 0, m.conversionError(to, err)
+		}
+		return m.fitFloat(to, f, bits)
+	}
+	v := reflect.ValueOf(m.value)
+	switch v.Kind() {
+	case reflect.String:
+		if m.opts.numeric == NumericWrap {
+			//strings are not wrapped, one that does not fit the size is an error the way it always has been
+			i, err := strconv.ParseInt(v.String(), 10, bits)
+			if err != nil {
+				return 0, m.conversionError(to, err)
+			}
+			return i, nil
+		}
+		//strict and saturate strings follow the same rules as numbers once they are parsed
+		i, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) && m.opts.numeric == NumericSaturate {
+				//ParseInt returns the nearest int64
+				return m.fitInt(to, i, bits)
+			}
+			return 0, m.conversionError(to, err)
+		}
+		return m.fitInt(to, i, bits)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return m.fitInt(to, v.Int(), bits)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return m.fitUint(to, v.Uint(), bits)
+	case reflect.Float32, reflect.Float64:
+		return m.fitFloat(to, v.Float(), bits)
+	}
+	return 0, m.conversionError(to, fmt.Errorf("unsupported type"))
+}
+
+// toUint64E converts the value to an unsigned integer of the size using the numeric mode
+// - to: the name of the type for errors
+// - bits: the size of the target type
+func (m *Data) toUint64E(to string, bits int) (uint64, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	switch val := m.value.(type) {
+	case nil:
+		return 0, m.conversionError(to, fmt.Errorf("no value"))
+	case bool:
+		if val {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number:
+		if u, err := strconv.ParseUint(val.String(), 10, 64); err == nil {
+			return m.fitUintSize(to, u, bits)
+		}
+		if i, err := val.Int64(); err == nil {
+			return m.fitNegative(to, i, bits)
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return 0, m.conversionError(to, err)
+		}
+		return m.fitFloatUint(to, f, bits)
+	}
+	v := reflect.ValueOf(m.value)
+	switch v.Kind() {
+	case reflect.String:
+		if m.opts.numeric == NumericWrap {
+			//strings are not wrapped, one that does not fit the size is an error
+			u, err := strconv.ParseUint(v.String(), 10, bits)
+			if err != nil {
+				return 0, m.conversionError(to, err)
+			}
+			return u, nil
+		}
+		//strict and saturate strings follow the same rules as numbers once they are parsed
+		u, err := strconv.ParseUint(v.String(), 10, 64)
+		if err == nil || (errors.Is(err, strconv.ErrRange) && m.opts.numeric == NumericSaturate) {
+			//ParseUint returns the largest uint64 when the value is too large
+			return m.fitUintSize(to, u, bits)
+		}
+		i, err_i := strconv.ParseInt(v.String(), 10, 64)
+		if err_i == nil || (errors.Is(err_i, strconv.ErrRange) && m.opts.numeric == NumericSaturate) {
+			//a negative number
+			return m.fitNegative(to, i, bits)
+		}
+		return 0, m.conversionError(to, err)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := v.Int(); i < 0 {
+			return m.fitNegative(to, i, bits)
+		}
+		return m.fitUintSize(to, uint64(v.Int()), bits)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return m.fitUintSize(to, v.Uint(), bits)
+	case reflect.Float32, reflect.Float64:
+		return m.fitFloatUint(to, v.Float(), bits)
+	}
+	return 0, m.conversionError(to, fmt.Errorf("unsupported type"))
+}
+
+// toFloat64E converts the value to a float of the size using the numeric mode
+// in strict mode integers that the float can not hold exactly return an error
+// - to: the name of the type for errors
+// - bits: the size of the target type
+func (m *Data) toFloat64E(to string, bits int) (float64, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	switch val := m.value.(type) {
+	case nil:
+		return 0, m.conversionError(to, fmt.Errorf("no value"))
+	case bool:
+		if val {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	v := reflect.ValueOf(m.value)
+	switch v.Kind() {
+	case reflect.String:
+		//json.Number is parsed as a string so it keeps its precision
+		f, err := strconv.ParseFloat(v.String(), bits)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) && m.opts.numeric == NumericSaturate {
+				return m.fitFloatSize(to, math.Copysign(math.MaxFloat64, f), bits)
+			}
+			return 0, m.conversionError(to, err)
+		}
+		return f, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		f := roundFloat(float64(i), bits)
+		if m.opts.numeric == NumericStrict && (f >= 0x1p63 || int64(f) != i) {
+			return 0, m.conversionError(to, ErrPrecision)
+		}
+		return float64(i), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		f := roundFloat(float64(u), bits)
+		if m.opts.numeric == NumericStrict && (f >= 0x1p64 || uint64(f) != u) {
+			return 0, m.conversionError(to, ErrPrecision)
+		}
+		return float64(u), nil
+	case reflect.Float32, reflect.Float64:
+		return m.fitFloatSize(to, v.Float(), bits)
+	}
+	return 0, m.conversionError(to, fmt.Errorf("unsupported type"))
+}
+
+// roundFloat rounds a float64 to the precision of the float size
+func roundFloat(f float64, bits int) float64 {
+	if bits == 32 {
+		return float64(float32(f))
+	}
+	return f
+}
+
+// convertTo converts the data to a go type using the To conversion for the type
+// structs and pointers are filled with Decode, slices and maps convert every item
+// - t: the type to convert to
+func (m *Data) convertTo(t reflect.Type) (reflect.Value, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	if v, ok, err := m.convertLeaf(t); ok {
+		return v, err
+	}
+	return m.convertKind(t)
+}
+
+// convertLeaf converts the data to the types that have their own To method
+// such as time.Time, netip.Addr and *big.Int, which are not read by their kind
+// returns false if the type is not one of them
+func (m *Data) convertLeaf(t reflect.Type) (reflect.Value, bool, error) {
+	var v interface{}
+	var err error
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		v, err = m.ToTimeE()
+	case reflect.TypeOf(time.Duration(0)):
+		v, err = m.ToDurationE()
+	case reflect.TypeOf(netip.Addr{}):
+		v, err = m.ToIPE()
+	case reflect.TypeOf(netip.Prefix{}):
+		v, err = m.ToIPPrefixE()
+	case reflect.TypeOf(&url.URL{}):
+		v, err = m.ToURLE()
+	case reflect.TypeOf(UUID{}):
+		v, err = m.ToUUIDE()
+	case reflect.TypeOf(Semver{}):
+		v, err = m.ToSemverE()
+	case reflect.TypeOf(&big.Int{}):
+		v, err = m.ToBigIntE()
+	case reflect.TypeOf(&big.Float{}):
+		v, err = m.ToBigFloatE()
+	case reflect.TypeOf(&big.Rat{}):
+		v, err = m.ToBigRatE()
+	default:
+		return reflect.Value{}, false, nil
+	}
+	if err != nil {
+		return reflect.Zero(t), true, err
+	}
+	return reflect.ValueOf(v), true, nil
+}
+
+// convertKind converts the data to a go type by its kind
+func (m *Data) convertKind(t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		s, err := m.ToStringE()
+		if err != nil {
+			return out, err
+		}
+		out.SetString(s)
+	case reflect.Bool:
+		b, err := m.ToBoolE()
+		if err != nil {
+			return out, err
+		}
+		out.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := m.toInt64E(t.String(), t.Bits())
+		if err != nil {
+			return out, err
+		}
+		out.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := m.toUint64E(t.String(), t.Bits())
+		if err != nil {
+			return out, err
+		}
+		out.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := m.toFloat64E(t.String(), t.Bits())
+		if err != nil {
+			return out, err
+		}
+		out.SetFloat(f)
+	case reflect.Interface:
+		if m.value == nil {
+			return out, nil
+		}
+		v := reflect.ValueOf(m.value)
+		if !v.Type().AssignableTo(t) {
+			return out, m.conversionError(t.String(), fmt.Errorf("does not implement %s", t))
+		}
+		out.Set(v)
+	case reflect.Slice:
+		if s, ok := m.value.(string); ok && t.Elem().Kind() == reflect.Uint8 {
+			out.SetBytes([]byte(s))
+			return out, nil
+		}
+		items, err := m.listItems()
+		if err != nil {
+			return out, err
+		}
+		out = reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			o, err := item.convertTo(t.Elem())
+			if err != nil {
+				return reflect.Zero(t), err
+			}
+			out.Index(i).Set(o)
+		}
+	case reflect.Map:
+		values, ok := asMap(m.value)
+		if !ok || t.Key().Kind() != reflect.String {
+			return out, m.conversionError(t.String(), fmt.Errorf("not a map"))
+		}
+		out = reflect.MakeMapWithSize(t, len(values))
+		for _, k := range sortedKeys(values) {
+			o, err := m.child(values[k], pathSegment{key: k}).convertTo(t.Elem())
+			if err != nil {
+				return reflect.Zero(t), err
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), o)
+		}
+	case reflect.Struct, reflect.Ptr, reflect.Array:
+		target := reflect.New(t)
+		if err := m.Decode(target.Interface()); err != nil {
+			return out, err
+		}
+		return target.Elem(), nil
+	default:
+		return out, m.conversionError(t.String(), fmt.Errorf("unsupported type"))
+	}
+	return out, nil
+}
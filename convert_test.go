@@ -0,0 +1,136 @@
+package go_data_chain
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestToE(t *testing.T) {
+	var test_data interface{}
+
+	file, _ := ioutil.ReadFile("examples/example_data.yaml")
+	err := yaml.Unmarshal([]byte(file), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	tests := []struct {
+		name    string
+		args    string
+		convert func(d *Data) (interface{}, error)
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "int_from_float", args: "data.convert_int.float_int", convert: func(d *Data) (interface{}, error) { return d.ToIntE() }, want: 1},
+		{name: "int_from_string", args: "data.convert_int.string_int", convert: func(d *Data) (interface{}, error) { return d.ToIntE() }, want: 2},
+		{name: "int_from_bad_string", args: "data.convert_string.string_string", convert: func(d *Data) (interface{}, error) { return d.ToIntE() }, want: 0, wantErr: true},
+		{name: "int_from_map", args: "data.maps", convert: func(d *Data) (interface{}, error) { return d.ToIntE() }, want: 0, wantErr: true},
+		{name: "int8_from_int", args: "data.convert_int.int_int", convert: func(d *Data) (interface{}, error) { return d.ToInt8E() }, want: int8(3)},
+		{name: "int32_from_bool", args: "data.convert_int.bool_int_true", convert: func(d *Data) (interface{}, error) { return d.ToInt32E() }, want: int32(1)},
+		{name: "int64_from_string", args: "data.convert_int.string_int", convert: func(d *Data) (interface{}, error) { return d.ToInt64E() }, want: int64(2)},
+		{name: "float32_from_string", args: "data.convert_float.string_float", convert: func(d *Data) (interface{}, error) { return d.ToFloat32E() }, want: float32(1.56)},
+		{name: "float64_from_int", args: "data.convert_float.int_float", convert: func(d *Data) (interface{}, error) { return d.ToFloat64E() }, want: 5.0},
+		{name: "float64_from_bad_string", args: "data.convert_string.string_string", convert: func(d *Data) (interface{}, error) { return d.ToFloat64E() }, want: 0.0, wantErr: true},
+		{name: "bool_from_yes", args: "data.convert_bool.string_bool_yes", convert: func(d *Data) (interface{}, error) { return d.ToBoolE() }, want: true},
+		{name: "bool_from_fail", args: "data.convert_bool.string_bool_fail", convert: func(d *Data) (interface{}, error) { return d.ToBoolE() }, want: false},
+		{name: "bool_from_bad_string", args: "data.convert_string.string_string", convert: func(d *Data) (interface{}, error) { return d.ToBoolE() }, want: false, wantErr: true},
+		{name: "string_from_float", args: "data.convert_string.float_string", convert: func(d *Data) (interface{}, error) { return d.ToStringE() }, want: "1.56"},
+		{name: "string_from_array", args: "data.arrays", convert: func(d *Data) (interface{}, error) { return d.ToStringE() }, want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.convert(chain.Get(tt.args))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConversionError(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{"port": "http"}, false)
+	_, err := chain.GetMapItem("port").ToIntE()
+	var conv_err *ConversionError
+	if !errors.As(err, &conv_err) {
+		t.Fatalf("ToIntE() error = %v, want a *ConversionError", err)
+	}
+	want := &ConversionError{Path: "/port", From: "string", To: "int", Value: "http", Err: conv_err.Err}
+	if !reflect.DeepEqual(conv_err, want) {
+		t.Errorf("ToIntE() error = %+v, want %+v", conv_err, want)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("ToIntE() error = %v, want it to wrap strconv.ErrSyntax", err)
+	}
+	//the lenient methods are unchanged
+	if got := chain.GetMapItem("port").ToInt(); got != 0 {
+		t.Errorf("ToInt() = %v, want 0", got)
+	}
+}
+
+func TestNumericMatrix(t *testing.T) {
+	sources := []interface{}{
+		int(7), int8(7), int16(7), int32(7), int64(7),
+		uint(7), uint8(7), uint16(7), uint32(7), uint64(7),
+		float32(7), float64(7), json.Number("7"),
+	}
+	for _, source := range sources {
+		chain := CreateDataChain(source, false)
+		got := []interface{}{
+			chain.ToInt(), chain.ToInt8(), chain.ToInt16(), chain.ToInt32(), chain.ToInt64(),
+			chain.ToUint(), chain.ToUint8(), chain.ToUint16(), chain.ToUint32(), chain.ToUint64(),
+			chain.ToFloat32(), chain.ToFloat64(), chain.ToBool(),
+		}
+		want := []interface{}{
+			int(7), int8(7), int16(7), int32(7), int64(7),
+			uint(7), uint8(7), uint16(7), uint32(7), uint64(7),
+			float32(7), float64(7), true,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%T: To() = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestJSONNumber(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`{"id": 9007199254740993, "price": 12.5, "negative": -1}`))
+	decoder.UseNumber()
+	var test_data interface{}
+	if err := decoder.Decode(&test_data); err != nil {
+		t.Fatal(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	if got := chain.GetMapItem("id").ToInt64(); got != 9007199254740993 {
+		t.Errorf("ToInt64() = %v, want 9007199254740993", got)
+	}
+	if got := chain.GetMapItem("id").ToUint64(); got != 9007199254740993 {
+		t.Errorf("ToUint64() = %v, want 9007199254740993", got)
+	}
+	if got := chain.GetMapItem("price").ToFloat64(); got != 12.5 {
+		t.Errorf("ToFloat64() = %v, want 12.5", got)
+	}
+	if got := chain.GetMapItem("price").ToInt(); got != 12 {
+		t.Errorf("ToInt() = %v, want 12", got)
+	}
+	if got := chain.GetMapItem("price").ToString(); got != "12.5" {
+		t.Errorf("ToString() = %v, want 12.5", got)
+	}
+	if _, err := chain.GetMapItem("negative").WithNumericMode(NumericStrict).ToUint8E(); !errors.Is(err, ErrOverflow) {
+		t.Errorf("ToUint8E() error = %v, want ErrOverflow", err)
+	}
+	if got := chain.GetMapItem("negative").WithNumericMode(NumericSaturate).ToUint8(); got != 0 {
+		t.Errorf("ToUint8() = %v, want 0", got)
+	}
+	if got := CreateDataChain(300, false).ToUint8(); got != 44 {
+		t.Errorf("ToUint8() = %v, want 44", got)
+	}
+}
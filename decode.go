@@ -0,0 +1,240 @@
+package go_data_chain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a single value that could not be decoded
+type FieldError struct {
+	// Path is the RFC 6901 JSON Pointer of the value
+	Path string
+	// Type is the go type the value should have been decoded into
+	Type string
+	Err  error
+}
+
+// Error returns the error as a string
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("`%s` to %s: %v", e.Path, e.Type, e.Err)
+}
+
+// Unwrap returns the reason the value could not be decoded
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError holds every value that could not be decoded
+type DecodeError struct {
+	Errors []*FieldError
+}
+
+// Error returns the errors as a string
+func (e *DecodeError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, f := range e.Errors {
+		parts = append(parts, f.Error())
+	}
+	return "decode failed: " + strings.Join(parts, "; ")
+}
+
+// Decode copies the data into a go value using the same lenient conversions as
+// the To methods, e.g. "yes" becomes true and 1.56 becomes 1
+// struct fields are matched by the `chain` tag, then the `json` and `yaml` tags,
+// then the field name ignoring case, embedded structs are flattened
+// - target: a pointer to the value to fill
+// returns a *DecodeError listing every value that failed
+func (m *Data) Decode(target interface{}) error {
+	if m == nil {
+		m = m.missingItem()
+	}
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non nil pointer, not %T", target)
+	}
+	d := &decoder{opts: m.opts}
+	d.decode(m.value, rv.Elem(), m.JSONPointer())
+	if len(d.errors) > 0 {
+		return &DecodeError{Errors: d.errors}
+	}
+	return nil
+}
+
+// decoder collects the errors while decoding
+type decoder struct {
+	errors []*FieldError
+	//opts are the conversion settings of the data being decoded
+	opts options
+}
+
+// fail records a value that could not be decoded
+func (d *decoder) fail(path string, target reflect.Value, format string, args ...interface{}) {
+	d.errors = append(d.errors, &FieldError{Path: path, Type: target.Type().String(), Err: fmt.Errorf(format, args...)})
+}
+
+// decode copies a value into the target
+func (d *decoder) decode(value interface{}, target reflect.Value, path string) {
+	if value == nil {
+		//leave the target as it is
+		return
+	}
+	//types such as time.Time and netip.Addr are structs so they are converted before looking at the kind
+	if v, ok, err := (&Data{value: value, opts: d.opts}).convertLeaf(target.Type()); ok {
+		if err != nil {
+			d.fail(path, target, "%w", errors.Unwrap(err))
+		} else {
+			target.Set(v)
+		}
+		return
+	}
+	switch target.Kind() {
+	case reflect.Ptr:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		d.decode(value, target.Elem(), path)
+	case reflect.Interface:
+		if target.NumMethod() == 0 {
+			target.Set(reflect.ValueOf(deepCopy(value)))
+			return
+		}
+		if v := reflect.ValueOf(value); v.Type().AssignableTo(target.Type()) {
+			target.Set(v)
+			return
+		}
+		d.fail(path, target, "`%T` does not implement %s", value, target.Type())
+	case reflect.Struct:
+		d.decodeStruct(value, target, path)
+	case reflect.Map:
+		items, ok := asMap(value)
+		if !ok {
+			d.fail(path, target, "not a map: `%T`", value)
+			return
+		}
+		if target.IsNil() {
+			target.Set(reflect.MakeMapWithSize(target.Type(), len(items)))
+		}
+		for _, k := range sortedKeys(items) {
+			key := reflect.New(target.Type().Key()).Elem()
+			d.decode(k, key, path+"/"+escapePointerToken(k))
+			elem := reflect.New(target.Type().Elem()).Elem()
+			d.decode(items[k], elem, path+"/"+escapePointerToken(k))
+			target.SetMapIndex(key, elem)
+		}
+	case reflect.Slice:
+		if s, ok := value.(string); ok && target.Type().Elem().Kind() == reflect.Uint8 {
+			target.SetBytes([]byte(s))
+			return
+		}
+		items, ok := asArray(value)
+		if !ok {
+			d.fail(path, target, "not an array: `%T`", value)
+			return
+		}
+		out := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, o := range items {
+			d.decode(o, out.Index(i), path+"/"+strconv.Itoa(i))
+		}
+		target.Set(out)
+	case reflect.Array:
+		items, ok := asArray(value)
+		if !ok {
+			d.fail(path, target, "not an array: `%T`", value)
+			return
+		}
+		if len(items) > target.Len() {
+			d.fail(path, target, "index out of range: `%v`", target.Len())
+			return
+		}
+		for i, o := range items {
+			d.decode(o, target.Index(i), path+"/"+strconv.Itoa(i))
+		}
+	default:
+		d.decodeScalar(value, target, path)
+	}
+}
+
+// decodeStruct copies the items of a map into the fields of a struct
+func (d *decoder) decodeStruct(value interface{}, target reflect.Value, path string) {
+	items, ok := asMap(value)
+	if !ok {
+		d.fail(path, target, "not a map: `%T`", value)
+		return
+	}
+	t := target.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, tagged := fieldName(field)
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && !tagged {
+			//flatten embedded structs
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fv := target.Field(i)
+				if fv.Kind() == reflect.Ptr {
+					if !fv.CanSet() {
+						continue
+					}
+					if fv.IsNil() {
+						fv.Set(reflect.New(ft))
+					}
+					fv = fv.Elem()
+				}
+				d.decodeStruct(value, fv, path)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			//unexported
+			continue
+		}
+		key, found := name, false
+		if _, found = items[name]; !found && !tagged {
+			for k := range items {
+				if strings.EqualFold(k, name) {
+					key, found = k, true
+					break
+				}
+			}
+		}
+		if found {
+			d.decode(items[key], target.Field(i), path+"/"+escapePointerToken(key))
+		}
+	}
+}
+
+// decodeScalar converts a value into a bool, number or string target
+// using the same conversions and numeric mode as the To methods
+func (d *decoder) decodeScalar(value interface{}, target reflect.Value, path string) {
+	switch target.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		if s, ok := value.(string); ok && s == "" && target.Kind() != reflect.String && target.Kind() != reflect.Bool {
+			//an empty string leaves a number as it is
+			return
+		}
+		out, err := (&Data{value: value, opts: d.opts}).convertKind(target.Type())
+		if err != nil {
+			d.fail(path, target, "%w", errors.Unwrap(err))
+			return
+		}
+		target.Set(out)
+	default:
+		v := reflect.ValueOf(value)
+		if v.Type().ConvertibleTo(target.Type()) {
+			target.Set(v.Convert(target.Type()))
+			return
+		}
+		d.fail(path, target, "can not convert `%T`", value)
+	}
+}
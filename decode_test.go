@@ -0,0 +1,197 @@
+package go_data_chain
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type decodeConvert struct {
+	FloatInt  int     `chain:"float_int"`
+	StringInt int64   `json:"string_int"`
+	IntInt    uint8   `yaml:"int_int"`
+	BoolInt   int     `chain:"bool_int_true"`
+	Float     float32 `chain:"float_float"`
+	Ignored   string  `chain:"-"`
+}
+
+type decodeBase struct {
+	Name string
+}
+
+type decodeServer struct {
+	decodeBase
+	Port    *int              `chain:"port"`
+	TLS     bool              `chain:"tls"`
+	Tags    []string          `chain:"tags"`
+	Limits  map[string]int    `chain:"limits"`
+	Backend *decodeServer     `chain:"backend"`
+	Extra   interface{}       `chain:"extra"`
+	Labels  map[string]string `chain:"labels"`
+}
+
+const decode_test_data = `
+name: web
+port: "8080"
+tls: "yes"
+tags: [a, b]
+limits:
+  cpu: 2
+  memory: "512"
+backend:
+  name: api
+  port: 9090
+extra: [1, 2]
+`
+
+func TestDecode(t *testing.T) {
+	var test_data interface{}
+
+	file, _ := ioutil.ReadFile("examples/example_data.yaml")
+	err := yaml.Unmarshal([]byte(file), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+
+	var convert decodeConvert
+	if err := chain.Get("data.convert_int").Decode(&convert); err != nil {
+		t.Fatal(err)
+	}
+	if want := (decodeConvert{FloatInt: 1, StringInt: 2, IntInt: 3, BoolInt: 1}); !reflect.DeepEqual(convert, want) {
+		t.Errorf("Decode() = %+v, want %+v", convert, want)
+	}
+
+	var server_data interface{}
+	if err := yaml.Unmarshal([]byte(decode_test_data), &server_data); err != nil {
+		t.Fatal(err)
+	}
+	var server decodeServer
+	if err := CreateDataChain(server_data, false).Decode(&server); err != nil {
+		t.Fatal(err)
+	}
+	port, backend_port := 8080, 9090
+	want := decodeServer{
+		decodeBase: decodeBase{Name: "web"},
+		Port:       &port,
+		TLS:        true,
+		Tags:       []string{"a", "b"},
+		Limits:     map[string]int{"cpu": 2, "memory": 512},
+		Backend:    &decodeServer{decodeBase: decodeBase{Name: "api"}, Port: &backend_port},
+		Extra:      []interface{}{1, 2},
+	}
+	if !reflect.DeepEqual(server, want) {
+		t.Errorf("Decode() = %+v, want %+v", server, want)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	data := map[string]interface{}{
+		"server": map[string]interface{}{
+			"port":   "http",
+			"tls":    "maybe",
+			"tags":   "a",
+			"limits": map[string]interface{}{"cpu": -1},
+		},
+	}
+	var target struct {
+		Port   int            `chain:"port"`
+		TLS    bool           `chain:"tls"`
+		Tags   []string       `chain:"tags"`
+		Limits map[string]int `chain:"limits"`
+		CPU    uint           `chain:"cpu"`
+	}
+	err := CreateDataChain(data, false).GetMapItem("server").Decode(&target)
+	var decode_err *DecodeError
+	if !errors.As(err, &decode_err) {
+		t.Fatalf("Decode() error = %v, want a *DecodeError", err)
+	}
+	var paths []string
+	for _, f := range decode_err.Errors {
+		paths = append(paths, f.Path)
+	}
+	if want := []string{"/server/port", "/server/tls", "/server/tags"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Decode() failed paths = %v, want %v", paths, want)
+	}
+
+	if err := CreateDataChain(data, false).Decode(target); err == nil {
+		t.Errorf("Decode() of a non pointer should fail")
+	}
+}
+
+func TestDecodeExactNumbers(t *testing.T) {
+	data := map[string]interface{}{
+		"big":      int64(9007199254740993),
+		"max_uint": json.Number("18446744073709551615"),
+		"small":    300,
+	}
+	var target struct {
+		Big     int64  `chain:"big"`
+		MaxUint uint64 `chain:"max_uint"`
+		Small   int8   `chain:"small"`
+	}
+	chain := CreateDataChain(data, false)
+	if err := chain.Decode(&target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Big != 9007199254740993 || target.MaxUint != math.MaxUint64 || target.Small != 44 {
+		t.Errorf("Decode() = %+v, want the exact values and 300 wrapped to 44", target)
+	}
+	if got, _ := GetAs[int64](chain, "big"); got != target.Big {
+		t.Errorf("GetAs[int64]() = %v, Decode() = %v", got, target.Big)
+	}
+
+	//the numeric mode of the chain applies to Decode
+	err := chain.WithNumericMode(NumericStrict).Decode(&target)
+	var decode_err *DecodeError
+	if !errors.As(err, &decode_err) || len(decode_err.Errors) != 1 || decode_err.Errors[0].Path != "/small" || !errors.Is(decode_err.Errors[0], ErrOverflow) {
+		t.Errorf("Decode() error = %v, want an overflow for /small", err)
+	}
+}
+
+func TestDecodeLeafTypes(t *testing.T) {
+	data := map[string]interface{}{
+		"client":  "10.0.0.1",
+		"subnet":  "10.0.0.0/8",
+		"api":     "https://api.example.com",
+		"id":      "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"version": "v1.2.3",
+		"total":   "12345678901234567890",
+		"timeout": "5s",
+		"bad_ip":  "10.0.0",
+	}
+	var target struct {
+		Client  netip.Addr
+		Subnet  netip.Prefix
+		API     *url.URL
+		ID      UUID
+		Version Semver
+		Total   *big.Int
+		Timeout time.Duration
+	}
+	if err := CreateDataChain(data, false).Decode(&target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Client.String() != "10.0.0.1" || target.Subnet.Bits() != 8 || target.API.Host != "api.example.com" ||
+		target.ID.Version() != 4 || target.Version != (Semver{Major: 1, Minor: 2, Patch: 3}) ||
+		target.Total.String() != "12345678901234567890" || target.Timeout != 5*time.Second {
+		t.Errorf("Decode() = %+v", target)
+	}
+
+	var bad struct {
+		BadIP netip.Addr `chain:"bad_ip"`
+	}
+	var decode_err *DecodeError
+	if err := CreateDataChain(data, false).Decode(&bad); !errors.As(err, &decode_err) || decode_err.Errors[0].Path != "/bad_ip" {
+		t.Errorf("Decode() error = %v, want an error for /bad_ip", err)
+	}
+}
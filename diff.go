@@ -0,0 +1,187 @@
+package go_data_chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind is the kind of difference found by Diff
+type ChangeKind int
+
+const (
+	// ChangeAdded is a value that only exists in the new tree
+	ChangeAdded ChangeKind = iota
+	// ChangeRemoved is a value that only exists in the old tree
+	ChangeRemoved
+	// ChangeModified is a value that exists in both trees with a different value
+	ChangeModified
+	// ChangeTypeChanged is a value that exists in both trees with a different type
+	ChangeTypeChanged
+)
+
+// String returns the kind as a string
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	case ChangeTypeChanged:
+		return "type-changed"
+	}
+	return "unknown"
+}
+
+// Change is a single difference between two trees
+type Change struct {
+	// Path is the RFC 6901 JSON Pointer of the value
+	Path string
+	Kind ChangeKind
+	Old  interface{}
+	New  interface{}
+}
+
+// String returns the change as a single line of text
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s: %s", c.Path, formatValue(c.New))
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s: %s", c.Path, formatValue(c.Old))
+	case ChangeTypeChanged:
+		return fmt.Sprintf("! %s: %s (%T) -> %s (%T)", c.Path, formatValue(c.Old), c.Old, formatValue(c.New), c.New)
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, formatValue(c.Old), formatValue(c.New))
+}
+
+// DiffOptions configures DiffWithOptions
+type DiffOptions struct {
+	// NumericEqual treats numbers of different types with the same value as equal
+	// so 1 and 1.0 are the same, the way ToFloat64 sees them
+	NumericEqual bool
+	// IgnorePaths are JSON Pointers that are skipped along with everything below them
+	IgnorePaths []string
+}
+
+// Diff returns the differences between two trees
+// - a: the old tree
+// - b: the new tree
+func Diff(a *Data, b *Data) []Change {
+	return DiffWithOptions(a, b, DiffOptions{})
+}
+
+// DiffWithOptions returns the differences between two trees
+// - a: the old tree
+// - b: the new tree
+// - opts: the options
+func DiffWithOptions(a *Data, b *Data, opts DiffOptions) []Change {
+	var changes []Change
+	diffValues(unwrapValue(a), unwrapValue(b), []string{}, opts, &changes)
+	return changes
+}
+
+// FormatChanges renders changes as human readable text, one change per line
+// - changes: the changes to render
+func FormatChanges(changes []Change) string {
+	var sb strings.Builder
+	for _, c := range changes {
+		sb.WriteString(c.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// ChangesToPatch converts changes to RFC 6902 JSON Patch operations
+// - changes: the changes returned by Diff
+func ChangesToPatch(changes []Change) []PatchOperation {
+	ops := make([]PatchOperation, 0, len(changes))
+	for _, c := range changes {
+		switch c.Kind {
+		case ChangeAdded:
+			ops = append(ops, PatchOperation{Op: "add", Path: c.Path, Value: c.New})
+		case ChangeRemoved:
+			ops = append(ops, PatchOperation{Op: "remove", Path: c.Path})
+		default:
+			ops = append(ops, PatchOperation{Op: "replace", Path: c.Path, Value: c.New})
+		}
+	}
+	return ops
+}
+
+// diffValues compares two values and records the changes below the path
+func diffValues(a interface{}, b interface{}, path []string, opts DiffOptions, changes *[]Change) {
+	pointer := FormatPointer(path)
+	for _, ignore := range opts.IgnorePaths {
+		if pointer == ignore || strings.HasPrefix(pointer, ignore+"/") {
+			return
+		}
+	}
+	items_a, a_is_map := asMap(a)
+	items_b, b_is_map := asMap(b)
+	if a_is_map && b_is_map {
+		for _, k := range sortedKeys(items_a) {
+			if o, ok := items_b[k]; ok {
+				diffValues(items_a[k], o, append(path, k), opts, changes)
+			} else {
+				diffRecord(Change{Path: FormatPointer(append(path, k)), Kind: ChangeRemoved, Old: items_a[k]}, opts, changes)
+			}
+		}
+		for _, k := range sortedKeys(items_b) {
+			if _, ok := items_a[k]; !ok {
+				diffRecord(Change{Path: FormatPointer(append(path, k)), Kind: ChangeAdded, New: items_b[k]}, opts, changes)
+			}
+		}
+		return
+	}
+	arr_a, a_is_array := asArray(a)
+	arr_b, b_is_array := asArray(b)
+	if a_is_array && b_is_array {
+		for i := 0; i < len(arr_a) && i < len(arr_b); i++ {
+			diffValues(arr_a[i], arr_b[i], append(path, strconv.Itoa(i)), opts, changes)
+		}
+		//remove from the end so the changes can be applied as a patch in order
+		for i := len(arr_a) - 1; i >= len(arr_b); i-- {
+			diffRecord(Change{Path: FormatPointer(append(path, strconv.Itoa(i))), Kind: ChangeRemoved, Old: arr_a[i]}, opts, changes)
+		}
+		for i := len(arr_a); i < len(arr_b); i++ {
+			diffRecord(Change{Path: FormatPointer(append(path, strconv.Itoa(i))), Kind: ChangeAdded, New: arr_b[i]}, opts, changes)
+		}
+		return
+	}
+	fa, a_is_number := asNumber(a)
+	fb, b_is_number := asNumber(b)
+	switch {
+	case a_is_number && b_is_number && opts.NumericEqual:
+		if fa != fb {
+			*changes = append(*changes, Change{Path: pointer, Kind: ChangeModified, Old: a, New: b})
+		}
+	case reflect.TypeOf(a) != reflect.TypeOf(b):
+		*changes = append(*changes, Change{Path: pointer, Kind: ChangeTypeChanged, Old: a, New: b})
+	case !reflect.DeepEqual(a, b):
+		*changes = append(*changes, Change{Path: pointer, Kind: ChangeModified, Old: a, New: b})
+	}
+}
+
+// diffRecord adds a change unless its path is ignored
+func diffRecord(c Change, opts DiffOptions, changes *[]Change) {
+	for _, ignore := range opts.IgnorePaths {
+		if c.Path == ignore || strings.HasPrefix(c.Path, ignore+"/") {
+			return
+		}
+	}
+	*changes = append(*changes, c)
+}
+
+// formatValue renders a value as json for messages
+func formatValue(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
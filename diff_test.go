@@ -0,0 +1,99 @@
+package go_data_chain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const diff_old_data = `
+server:
+  host: localhost
+  port: 8080
+  timeout: 30
+  tags: [a, b, c]
+replicas: 2
+`
+
+const diff_new_data = `
+server:
+  port: "8080"
+  timeout: 30.0
+  tags: [a, x]
+  tls: true
+replicas: 3
+`
+
+func TestDiff(t *testing.T) {
+	var old_data, new_data interface{}
+	if err := yaml.Unmarshal([]byte(diff_old_data), &old_data); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal([]byte(diff_new_data), &new_data); err != nil {
+		t.Fatal(err)
+	}
+	a := CreateDataChain(old_data, false)
+	b := CreateDataChain(new_data, false)
+	tests := []struct {
+		name string
+		opts DiffOptions
+		want []Change
+	}{
+		{
+			name: "default",
+			opts: DiffOptions{},
+			want: []Change{
+				{Path: "/replicas", Kind: ChangeModified, Old: 2, New: 3},
+				{Path: "/server/host", Kind: ChangeRemoved, Old: "localhost"},
+				{Path: "/server/port", Kind: ChangeTypeChanged, Old: 8080, New: "8080"},
+				{Path: "/server/tags/1", Kind: ChangeModified, Old: "b", New: "x"},
+				{Path: "/server/tags/2", Kind: ChangeRemoved, Old: "c"},
+				{Path: "/server/timeout", Kind: ChangeTypeChanged, Old: 30, New: 30.0},
+				{Path: "/server/tls", Kind: ChangeAdded, New: true},
+			},
+		},
+		{
+			name: "numeric_equal_and_ignore",
+			opts: DiffOptions{NumericEqual: true, IgnorePaths: []string{"/server/tags", "/replicas"}},
+			want: []Change{
+				{Path: "/server/host", Kind: ChangeRemoved, Old: "localhost"},
+				{Path: "/server/port", Kind: ChangeTypeChanged, Old: 8080, New: "8080"},
+				{Path: "/server/tls", Kind: ChangeAdded, New: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffWithOptions(a, b, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffRender(t *testing.T) {
+	var old_data, new_data interface{}
+	if err := yaml.Unmarshal([]byte(diff_old_data), &old_data); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal([]byte(diff_new_data), &new_data); err != nil {
+		t.Fatal(err)
+	}
+	changes := Diff(CreateDataChain(old_data, false), CreateDataChain(new_data, false))
+	text := FormatChanges(changes)
+	for _, want := range []string{`~ /replicas: 2 -> 3`, `- /server/host: "localhost"`, `+ /server/tls: true`, `! /server/port: 8080 (int) -> "8080" (string)`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatChanges() = %v, want it to contain %v", text, want)
+		}
+	}
+	//applying the patch to the old tree must give the new tree
+	chain := CreateDataChain(old_data, false)
+	if err := chain.ApplyPatch(ChangesToPatch(changes)); err != nil {
+		t.Fatal(err)
+	}
+	if got := Diff(chain, CreateDataChain(new_data, false)); len(got) != 0 {
+		t.Errorf("Diff() after ApplyPatch() = %v, want no changes", got)
+	}
+}
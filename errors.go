@@ -0,0 +1,151 @@
+package go_data_chain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrKeyNotFound is matched by errors.Is for every KeyNotFoundError
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrIndexOutOfRange is matched by errors.Is for every IndexOutOfRangeError
+	ErrIndexOutOfRange = errors.New("index out of range")
+	// ErrTypeMismatch is matched by errors.Is for every TypeMismatchError
+	ErrTypeMismatch = errors.New("type mismatch")
+)
+
+// KeyNotFoundError is returned when a map does not have a key
+type KeyNotFoundError struct {
+	// Path is the RFC 6901 JSON Pointer of the missing item
+	Path string
+	Key  string
+	// Value is the map the key was read from
+	Value interface{}
+}
+
+// Error returns the error as a string
+func (e *KeyNotFoundError) Error() string {
+	return fmt.Sprintf("key `%s` does not exist at `%s`", e.Key, e.Path)
+}
+
+// Is reports whether the target is ErrKeyNotFound
+func (e *KeyNotFoundError) Is(target error) bool {
+	return target == ErrKeyNotFound
+}
+
+// IndexOutOfRangeError is returned when an array does not have an index
+type IndexOutOfRangeError struct {
+	// Path is the RFC 6901 JSON Pointer of the missing item
+	Path   string
+	Index  int
+	Length int
+	// Value is the array the index was read from
+	Value interface{}
+}
+
+// Error returns the error as a string
+func (e *IndexOutOfRangeError) Error() string {
+	return fmt.Sprintf("index out of range: `%v` at `%s` with length %v", e.Index, e.Path, e.Length)
+}
+
+// Is reports whether the target is ErrIndexOutOfRange
+func (e *IndexOutOfRangeError) Is(target error) bool {
+	return target == ErrIndexOutOfRange
+}
+
+// TypeMismatchError is returned when a value is not the map or array a step needs
+type TypeMismatchError struct {
+	// Path is the RFC 6901 JSON Pointer of the value
+	Path string
+	// Want is the kind the step needs, map or array
+	Want string
+	// Got is the kind of the value
+	Got   string
+	Value interface{}
+}
+
+// Error returns the error as a string
+func (e *TypeMismatchError) Error() string {
+	article := "a"
+	if e.Want != "" && strings.IndexByte("aeiou", e.Want[0]) >= 0 {
+		article = "an"
+	}
+	return fmt.Sprintf("not %s %s: `%s` at `%s`", article, e.Want, e.Got, e.Path)
+}
+
+// Is reports whether the target is ErrTypeMismatch
+func (e *TypeMismatchError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+// MultiError holds every error added to a safe chain in the order they happened
+// errors.Is and errors.As match any of the errors
+type MultiError struct {
+	Errors []error
+}
+
+// Error returns the errors as a string, each followed by `; `
+func (e *MultiError) Error() string {
+	var sb strings.Builder
+	for _, err := range e.Errors {
+		sb.WriteString(err.Error())
+		sb.WriteString("; ")
+	}
+	return sb.String()
+}
+
+// Unwrap returns the errors
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether any of the errors matches the target
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches the target
+func (e *MultiError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendError returns a MultiError with the error added
+// a new MultiError is made each time as copies of a chain may share the old one
+// - errs: the errors so far, a MultiError, a single error or nil
+// - err: the error to add
+func appendError(errs error, err error) error {
+	var list []error
+	if multi, ok := errs.(*MultiError); ok {
+		list = append(list, multi.Errors...)
+	} else if errs != nil {
+		list = append(list, errs)
+	}
+	return &MultiError{Errors: append(list, err)}
+}
+
+// keyNotFound creates a KeyNotFoundError for a key of the data
+func (m *Data) keyNotFound(key string) error {
+	return &KeyNotFoundError{Path: m.child(nil, pathSegment{key: key}).JSONPointer(), Key: key, Value: m.value}
+}
+
+// indexOutOfRange creates an IndexOutOfRangeError for an index of the data
+func (m *Data) indexOutOfRange(index int, length int) error {
+	return &IndexOutOfRangeError{Path: m.child(nil, pathSegment{index: index, isIndex: true}).JSONPointer(), Index: index, Length: length, Value: m.value}
+}
+
+// typeMismatch creates a TypeMismatchError for the data
+// - want: the kind the step needs, map or array
+func (m *Data) typeMismatch(want string) error {
+	return &TypeMismatchError{Path: m.JSONPointer(), Want: want, Got: m.valueKind(), Value: m.value}
+}
@@ -0,0 +1,86 @@
+package go_data_chain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTypedErrors(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{
+		"server": map[string]interface{}{"name": "web", "ports": []interface{}{80}},
+	}, true)
+	chain.Get("server.host")
+	chain.Get("server.ports[3]")
+	chain.Get("server.name.first")
+	chain.GetMapItem("server").GetMapItem("name").GetArrayCount()
+	chain.Get("server.ports[0]").ToURL()
+
+	var multi *MultiError
+	if !errors.As(chain.Err, &multi) {
+		t.Fatalf("Err = %T, want a *MultiError", chain.Err)
+	}
+	want := []string{
+		"key `host` does not exist at `/server/host`",
+		"index out of range: `3` at `/server/ports/3` with length 1",
+		"not a map: `string` at `/server/name`",
+		"not an array: `string` at `/server/name`",
+	}
+	if len(multi.Errors) != len(want)+1 {
+		t.Fatalf("Errors = %v, want %v errors", multi.Errors, len(want)+1)
+	}
+	for i, w := range want {
+		if multi.Errors[i].Error() != w {
+			t.Errorf("Errors[%v] = %v, want %v", i, multi.Errors[i], w)
+		}
+	}
+
+	var key_err *KeyNotFoundError
+	if !errors.As(chain.Err, &key_err) || key_err.Key != "host" || key_err.Path != "/server/host" {
+		t.Errorf("errors.As() KeyNotFoundError = %v", key_err)
+	}
+	var index_err *IndexOutOfRangeError
+	if !errors.As(chain.Err, &index_err) || index_err.Index != 3 || index_err.Length != 1 {
+		t.Errorf("errors.As() IndexOutOfRangeError = %v", index_err)
+	}
+	var type_err *TypeMismatchError
+	if !errors.As(chain.Err, &type_err) || type_err.Want != "map" || type_err.Value != "web" {
+		t.Errorf("errors.As() TypeMismatchError = %v", type_err)
+	}
+	var conv_err *ConversionError
+	if !errors.As(chain.Err, &conv_err) || conv_err.Path != "/server/ports/0" {
+		t.Errorf("errors.As() ConversionError = %v", conv_err)
+	}
+	for _, target := range []error{ErrKeyNotFound, ErrIndexOutOfRange, ErrTypeMismatch} {
+		if !errors.Is(chain.Err, target) {
+			t.Errorf("errors.Is(%v) = false", target)
+		}
+	}
+	if errors.Is(chain.Err, ErrOverflow) {
+		t.Errorf("errors.Is(ErrOverflow) = true")
+	}
+	//a zero value can be formatted
+	if got := (&TypeMismatchError{}).Error(); got != "not a : `` at ``" {
+		t.Errorf("TypeMismatchError{}.Error() = %v", got)
+	}
+}
+
+func TestMutateErrors(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{"items": []interface{}{1}, "name": "web"}, false)
+	tests := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{name: "set_in_string", err: chain.GetMapItem("name").SetMapItem("a", 1), target: ErrTypeMismatch},
+		{name: "set_out_of_range", err: chain.GetMapItem("items").SetArrayItem(5, 1), target: ErrIndexOutOfRange},
+		{name: "delete_missing_key", err: chain.DeleteMapItem("other"), target: ErrKeyNotFound},
+		{name: "append_to_string", err: chain.GetMapItem("name").Append(1), target: ErrTypeMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.target) {
+				t.Errorf("error = %v, want %v", tt.err, tt.target)
+			}
+		})
+	}
+}
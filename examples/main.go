@@ -67,7 +67,6 @@ func main() {
 	//*****************************************************************************************
 	// Create an instance if go_data_chain but with safe parameter to true
 	// This will always return a chain item even if the data does not exist
-	// For this to work you must use the GetMapItem(), GetArrayItem() and GetArrayCount methods
 	// And check the Err property of the chain item for errors
 	// This is a workaround so that if data does not exist the program does not crash
 	// And give you the option to handle the error
@@ -81,15 +80,18 @@ func main() {
 	fmt.Println(chain_item.GetMapItem("map_string_1").ToString())                  //cast as a string
 	if chain_error.Err != nil {
 		fmt.Printf("there was an error for GetMapItem:%v \n", chain_error.Err)
-		chain_error.Err = nil //reset the error
+		chain_error.Reset() //reset the error
 	}
 
 	//********************************
 	//Get an array that does not exist
 	//********************************
-	chain_item = chain_error.GetMapItem("data").GetMapItem("arrays_does_not_exists") //get a map item
-	if chain_item.Err != nil {
-		fmt.Printf("there was an error for GetMapItem:%v \n", chain_error.Err)
+	//the scope collects its own errors so the root is left untouched
+	err = chain_error.Try(func(scope *go_data_chain.Data) {
+		chain_item = scope.GetMapItem("data").GetMapItem("arrays_does_not_exists") //get a map item
+	})
+	if err != nil {
+		fmt.Printf("there was an error for GetMapItem:%v \n", err)
 	} else {
 		fmt.Print(chain_item.ToString())
 	}
@@ -97,7 +99,6 @@ func main() {
 	//****************************************
 	//Because the array does not exist
 	//The GetArrayCount() method will return 0
-	// And return an error
 	//****************************************
 	for i := 0; i < chain_item.GetArrayCount(); i++ {
 		fmt.Println(chain_item.GetArrayItem(i).ToString())
@@ -0,0 +1,85 @@
+package go_data_chain
+
+import (
+	"time"
+)
+
+// ToStringOr returns the data as a string or the default if it is missing, null or can not be converted
+// the default is returned without adding an error to a safe chain
+// - def: the default value
+func (m *Data) ToStringOr(def string) string {
+	return AsOr(m, def)
+}
+
+// ToIntOr returns the data as an int or the default if it is missing, null or can not be converted
+// - def: the default value
+func (m *Data) ToIntOr(def int) int {
+	return AsOr(m, def)
+}
+
+// ToInt64Or returns the data as an int64 or the default if it is missing, null or can not be converted
+// - def: the default value
+func (m *Data) ToInt64Or(def int64) int64 {
+	return AsOr(m, def)
+}
+
+// ToUint64Or returns the data as a uint64 or the default if it is missing, null or can not be converted
+// - def: the default value
+func (m *Data) ToUint64Or(def uint64) uint64 {
+	return AsOr(m, def)
+}
+
+// ToFloat64Or returns the data as a float64 or the default if it is missing, null or can not be converted
+// - def: the default value
+func (m *Data) ToFloat64Or(def float64) float64 {
+	return AsOr(m, def)
+}
+
+// ToBoolOr returns the data as a bool or the default if it is missing, null or can not be converted
+// - def: the default value
+func (m *Data) ToBoolOr(def bool) bool {
+	return AsOr(m, def)
+}
+
+// ToDurationOr returns the data as a time.Duration or the default if it is missing, null or can not be converted
+// - def: the default value
+func (m *Data) ToDurationOr(def time.Duration) time.Duration {
+	return AsOr(m, def)
+}
+
+// ToTimeOr returns the data as a time.Time or the default if it is missing, null or can not be converted
+// - def: the default value
+func (m *Data) ToTimeOr(def time.Time) time.Time {
+	return AsOr(m, def)
+}
+
+// FirstOf returns the first item that exists and is not null, trying the path expressions in order
+// paths that do not exist do not add errors to a safe chain, so renamed keys can be read as
+// chain.FirstOf("timeout", "timeout_seconds").ToIntOr(30)
+// - paths: the path expressions, see CompilePath for the grammar
+// returns a missing Data object if no item is found
+func (m *Data) FirstOf(paths ...string) *Data {
+	items := make([]*Data, 0, len(paths))
+	for _, path := range paths {
+		if item, err := m.lookupPath(path); err == nil {
+			items = append(items, item)
+		}
+	}
+	if item := Coalesce(items...); item.Exists() {
+		return item
+	}
+	return m.missingItem()
+}
+
+// Coalesce returns the first item that exists and is not null
+// nil and the missing items returned for a key or index that does not exist are skipped
+// - items: the items to check
+// returns a missing Data object if no item is found
+func Coalesce(items ...*Data) *Data {
+	for _, item := range items {
+		if item != nil && !item.missing && item.value != nil {
+			return item
+		}
+	}
+	return (*Data)(nil).missingItem()
+}
@@ -0,0 +1,81 @@
+package go_data_chain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrDefaults(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{
+		"port":    "8080",
+		"debug":   "true",
+		"ratio":   "0.25",
+		"timeout": "5s",
+		"name":    "web",
+		"empty":   nil,
+	}, true)
+	if got := chain.FirstOf("port").ToIntOr(80); got != 8080 {
+		t.Errorf("ToIntOr() = %v, want 8080", got)
+	}
+	if got := chain.FirstOf("name").ToIntOr(80); got != 80 {
+		t.Errorf("ToIntOr() = %v, want the default", got)
+	}
+	if got := chain.FirstOf("missing").ToStringOr("none"); got != "none" {
+		t.Errorf("ToStringOr() = %v, want the default", got)
+	}
+	if got := chain.FirstOf("empty").ToBoolOr(true); !got {
+		t.Errorf("ToBoolOr() = %v, want the default", got)
+	}
+	if got := chain.FirstOf("debug").ToBoolOr(false); !got {
+		t.Errorf("ToBoolOr() = %v, want true", got)
+	}
+	if got := chain.FirstOf("ratio").ToFloat64Or(1); got != 0.25 {
+		t.Errorf("ToFloat64Or() = %v, want 0.25", got)
+	}
+	if got := chain.FirstOf("timeout").ToDurationOr(time.Minute); got != 5*time.Second {
+		t.Errorf("ToDurationOr() = %v, want 5s", got)
+	}
+	if got := chain.FirstOf("name").ToDurationOr(time.Minute); got != time.Minute {
+		t.Errorf("ToDurationOr() = %v, want the default", got)
+	}
+	if chain.Err != nil {
+		t.Errorf("defaults should not add errors, Err = %v", chain.Err)
+	}
+
+	//a chain that is not safe returns a missing item, which still gives the default
+	if got := CreateDataChain(map[string]interface{}{}, false).FirstOf("port").ToIntOr(80); got != 80 {
+		t.Errorf("ToIntOr() = %v, want the default", got)
+	}
+}
+
+func TestFirstOf(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{
+		"server": map[string]interface{}{"listen_port": 9090, "port": nil},
+	}, true)
+	tests := []struct {
+		name  string
+		paths []string
+		want  interface{}
+	}{
+		{name: "renamed_key", paths: []string{"server.port", "server.listen_port"}, want: 9090},
+		{name: "missing_first", paths: []string{"server.http_port", "server.listen_port"}, want: 9090},
+		{name: "none", paths: []string{"server.port", "port"}, want: nil},
+		{name: "invalid_path", paths: []string{"server..port", "server.listen_port"}, want: 9090},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chain.FirstOf(tt.paths...).ToInterface(); got != tt.want {
+				t.Errorf("FirstOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if chain.Err != nil {
+		t.Errorf("FirstOf() should not add errors, Err = %v", chain.Err)
+	}
+	if got := Coalesce(nil, chain.GetMapItem("server").GetMapItem("port"), chain.Get("server.listen_port")); got.ToInt() != 9090 {
+		t.Errorf("Coalesce() = %v, want 9090", got.ToInterface())
+	}
+	if got := Coalesce(nil, chain.Get("server.none")); got == nil || got.Exists() {
+		t.Errorf("Coalesce() = %v, want a missing item", got)
+	}
+}
@@ -0,0 +1,170 @@
+package go_data_chain
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// As returns the data converted to T
+// built in kinds use the same conversion as their To method, structs are filled with Decode
+// - d: the data to convert
+func As[T any](d *Data) (T, error) {
+	var out T
+	if d == nil {
+		return out, fmt.Errorf("no data")
+	}
+	v, err := d.convertTo(reflect.TypeOf(&out).Elem())
+	if err != nil {
+		return out, err
+	}
+	return v.Interface().(T), nil
+}
+
+// GetAs returns the item at the path expression converted to T
+// the lookup does not add errors to a safe chain, a missing item is returned as the error
+// - d: the data to read from
+// - path: the path expression, see CompilePath for the grammar
+func GetAs[T any](d *Data, path string) (T, error) {
+	var out T
+	item, err := d.lookupPath(path)
+	if err != nil {
+		return out, err
+	}
+	return As[T](item)
+}
+
+// AsOr returns the data converted to T or the default if it is missing, null or can not be converted
+// - d: the data to convert
+// - def: the value returned when the data can not be converted
+func AsOr[T any](d *Data, def T) T {
+	if d == nil || d.value == nil {
+		return def
+	}
+	v, err := As[T](d)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// lookupPath resolves a path expression without adding errors to the chain
+func (m *Data) lookupPath(path string) (*Data, error) {
+	if m == nil {
+		return nil, fmt.Errorf("no data")
+	}
+	p, err := CompilePath(path)
+	if err != nil {
+		return nil, err
+	}
+	item, ok := m.lookup(p)
+	if !ok {
+		return nil, fmt.Errorf("path `%s` does not exist", path)
+	}
+	return item, nil
+}
+
+//***************
+//Optional values
+//***************
+
+// optionalState records whether an Optional value was missing, null, present or present but not convertible
+type optionalState int
+
+const (
+	optionalMissing optionalState = iota
+	optionalNull
+	optionalPresent
+	optionalInvalid
+)
+
+// Optional is a value that may be missing or null, unlike the To methods
+// which return the zero value for both
+type Optional[T any] struct {
+	value T
+	state optionalState
+}
+
+// IsMissing returns true if the item does not exist
+func (o Optional[T]) IsMissing() bool {
+	return o.state == optionalMissing
+}
+
+// IsNull returns true if the item exists and is null
+func (o Optional[T]) IsNull() bool {
+	return o.state == optionalNull
+}
+
+// IsPresent returns true if the item exists and has a value, even one that could not be converted
+func (o Optional[T]) IsPresent() bool {
+	return o.state == optionalPresent || o.state == optionalInvalid
+}
+
+// IsInvalid returns true if the item exists but its value could not be converted to T
+func (o Optional[T]) IsInvalid() bool {
+	return o.state == optionalInvalid
+}
+
+// Get returns the value and true if it is present and was converted
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.state == optionalPresent
+}
+
+// OrElse returns the value if it is present and was converted or the default
+// - def: the value returned when the item is missing, null or invalid
+func (o Optional[T]) OrElse(def T) T {
+	if o.state == optionalPresent {
+		return o.value
+	}
+	return def
+}
+
+// String returns the value, `<missing>`, `<null>` or `<invalid>`
+func (o Optional[T]) String() string {
+	switch o.state {
+	case optionalMissing:
+		return "<missing>"
+	case optionalNull:
+		return "<null>"
+	case optionalInvalid:
+		return "<invalid>"
+	}
+	return fmt.Sprint(o.value)
+}
+
+// AsOptional returns the data converted to T as an Optional
+// nil and the item returned for a key or index that does not exist are missing
+// - d: the data to convert
+// returns an error only if the value is present and can not be converted
+func AsOptional[T any](d *Data) (Optional[T], error) {
+	if d == nil || d.missing {
+		return Optional[T]{state: optionalMissing}, nil
+	}
+	if d.value == nil {
+		return Optional[T]{state: optionalNull}, nil
+	}
+	v, err := As[T](d)
+	if err != nil {
+		return Optional[T]{state: optionalInvalid}, err
+	}
+	return Optional[T]{value: v, state: optionalPresent}, nil
+}
+
+// GetOptional returns the item at the path expression converted to T as an Optional
+// the lookup does not add errors to a safe chain
+// - d: the data to read from
+// - path: the path expression, see CompilePath for the grammar
+// returns an error if the path is invalid or the value is present and can not be converted
+func GetOptional[T any](d *Data, path string) (Optional[T], error) {
+	if d == nil {
+		return Optional[T]{state: optionalMissing}, nil
+	}
+	p, err := CompilePath(path)
+	if err != nil {
+		return Optional[T]{state: optionalMissing}, err
+	}
+	item, ok := d.lookup(p)
+	if !ok {
+		return Optional[T]{state: optionalMissing}, nil
+	}
+	return AsOptional[T](item)
+}
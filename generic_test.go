@@ -0,0 +1,100 @@
+package go_data_chain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAs(t *testing.T) {
+	type server struct {
+		Host    string
+		Port    int
+		Timeout time.Duration
+	}
+	chain := CreateDataChain(map[string]interface{}{
+		"port":    "8080",
+		"enabled": "yes",
+		"server":  map[string]interface{}{"host": "localhost", "port": 80, "timeout": "30s"},
+		"tags":    []interface{}{"a", "b"},
+		"empty":   nil,
+	}, true)
+
+	if got, err := GetAs[int](chain, "port"); err != nil || got != 8080 {
+		t.Errorf("GetAs[int]() = %v, %v", got, err)
+	}
+	if got, err := GetAs[bool](chain, "enabled"); err != nil || !got {
+		t.Errorf("GetAs[bool]() = %v, %v", got, err)
+	}
+	if got, err := GetAs[server](chain, "server"); err != nil || !reflect.DeepEqual(got, server{Host: "localhost", Port: 80, Timeout: 30 * time.Second}) {
+		t.Errorf("GetAs[server]() = %v, %v", got, err)
+	}
+	if got, err := As[[]string](chain.GetMapItem("tags")); err != nil || !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("As[[]string]() = %v, %v", got, err)
+	}
+	if _, err := GetAs[int](chain, "server.host"); err == nil {
+		t.Errorf("GetAs[int]() of a host name should fail")
+	}
+	if _, err := GetAs[int](chain, "server.missing"); err == nil {
+		t.Errorf("GetAs[int]() of a missing path should fail")
+	}
+	if got := AsOr(chain.GetMapItem("port"), 1); got != 8080 {
+		t.Errorf("AsOr() = %v, want 8080", got)
+	}
+	if got := AsOr(chain.GetMapItem("server").GetMapItem("host"), 1); got != 1 {
+		t.Errorf("AsOr() = %v, want the default", got)
+	}
+	if got := AsOr[int](nil, 1); got != 1 {
+		t.Errorf("AsOr() = %v, want the default", got)
+	}
+}
+
+func TestOptional(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{
+		"port":  8080,
+		"empty": nil,
+		"name":  "web",
+	}, true)
+	tests := []struct {
+		name      string
+		path      string
+		wantState string
+		wantValue int
+		wantErr   bool
+	}{
+		{name: "present", path: "port", wantState: "present", wantValue: 8080},
+		{name: "null", path: "empty", wantState: "null"},
+		{name: "missing", path: "other", wantState: "missing"},
+		{name: "missing_below", path: "port.value", wantState: "missing"},
+		{name: "not_a_number", path: "name", wantState: "invalid", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetOptional[int](chain, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetOptional() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			state := map[bool]string{got.IsMissing(): "missing", got.IsNull(): "null", got.IsPresent() && !got.IsInvalid(): "present", got.IsInvalid(): "invalid"}[true]
+			if state != tt.wantState {
+				t.Errorf("GetOptional() state = %v, want %v", state, tt.wantState)
+			}
+			if got.OrElse(0) != tt.wantValue {
+				t.Errorf("GetOptional() value = %v, want %v", got.OrElse(0), tt.wantValue)
+			}
+		})
+	}
+	if chain.Err != nil {
+		t.Errorf("GetOptional() should not add errors, Err = %v", chain.Err)
+	}
+
+	//the placeholders returned in safe mode keep the difference
+	if got, _ := AsOptional[int](chain.GetMapItem("other")); !got.IsMissing() {
+		t.Errorf("AsOptional() = %v, want missing", got)
+	}
+	if got, _ := AsOptional[int](chain.GetMapItem("empty")); !got.IsNull() {
+		t.Errorf("AsOptional() = %v, want null", got)
+	}
+	if got, _ := AsOptional[int](chain.GetMapItem("port")); got.String() != "8080" {
+		t.Errorf("AsOptional() = %v, want 8080", got)
+	}
+}
@@ -5,15 +5,31 @@ package go_data_chain
 import (
 	"fmt"
 	"reflect"
-	"strconv"
-	"strings"
+	"time"
 )
 
 //Data is a struct that can hold any type of data
 type Data struct {
-	Err    error
-	parent interface{}
-	value  interface{}
+	Err error
+	//errSink is the root of a safe chain or the scope whose Err collects the errors, nil if errors are not kept
+	errSink *Data
+	value   interface{}
+	//up is the map or array the data was read from, see Parent
+	up   *Data
+	at   *pathSegment
+	opts options
+	//missing is set on the item returned for a key or index that does not exist
+	missing bool
+}
+
+// options are the conversion settings passed from a Data object to the items read from it
+type options struct {
+	//numeric is the mode used by number conversions
+	numeric NumericMode
+	//durationUnit is the unit of plain numbers read as durations, zero means seconds
+	durationUnit time.Duration
+	//list configures how values that are not arrays are read as lists
+	list ListOptions
 }
 
 // CreateDynamicData creates a new Data object
@@ -22,270 +38,143 @@ type Data struct {
 func CreateDataChain(value interface{}, safe bool) *Data {
 	data := Data{value: value}
 	if safe {
-		data.parent = &data
+		data.errSink = &data
 	}
 	return &data
 }
 
 // GetType returns the type of the data as a string
+// missing data and nil return invalid
 func (m *Data) GetType() string {
+	if m == nil || m.value == nil {
+		return reflect.Invalid.String()
+	}
 	//Return the type as a string
 	return reflect.TypeOf(m.value).Kind().String()
 }
 
 // ToString returns the data as a string
+// missing data returns an empty string
 func (m *Data) ToString() string {
+	if !m.Exists() {
+		return ""
+	}
 	//check if the value is a string
 	if m.value != nil && reflect.TypeOf(m.value).Kind() == reflect.String {
-		return m.value.(string)
+		return reflect.ValueOf(m.value).String()
 	}
 	return fmt.Sprintf("%v", m.value)
 }
 
 // ToInt returns the data as an int
 func (m *Data) ToInt() int {
-	switch val := m.value.(type) {
-	case bool:
-		if val {
-			return 1
-		}
-		return 0
-	case string:
-		//convert the string to a int64
-		if val == "" {
-			return 0
-		}
-		i, err := strconv.ParseInt(val, 10, 64)
-		if err != nil {
-			return 0
-		}
-		return int(i)
-	case int:
-		return val
-	case float32:
-		return int(val)
-	case float64:
-		return int(val)
-	case int64:
-		return int(val)
-	case int8:
-		return int(val)
-	default:
-		return 0
-	}
+	v, err := m.ToIntE()
+	m.recordError(err)
+	return v
 }
 
 // ToInt8 returns the data as an int8
 func (m *Data) ToInt8() int8 {
-	switch val := m.value.(type) {
-	case bool:
-		if val {
-			return 1
-		}
-		return 0
-	case string:
-		//convert the string to a int64
-		if val == "" {
-			return 0
-		}
-		i, err := strconv.ParseInt(val, 10, 64)
-		if err != nil {
-			return 0
-		}
-		return int8(i)
-	case int:
-		return int8(val)
-	case float32:
-		return int8(val)
-	case float64:
-		return int8(val)
-	case int64:
-		return int8(val)
-	case int8:
-		return val
-	default:
-		return 0
-	}
+	v, err := m.ToInt8E()
+	m.recordError(err)
+	return v
+}
+
+// ToInt16 returns the data as an int16
+func (m *Data) ToInt16() int16 {
+	v, err := m.ToInt16E()
+	m.recordError(err)
+	return v
 }
 
 // ToInt32 returns the data as an int32
 func (m *Data) ToInt32() int32 {
-	switch val := m.value.(type) {
-	case bool:
-		if val {
-			return 1
-		}
-		return 0
-	case string:
-		//convert the string to a int64
-		if val == "" {
-			return 0
-		}
-		i, err := strconv.ParseInt(val, 10, 32)
-		if err != nil {
-			return 0
-		}
-		return int32(i)
-	case int:
-		return int32(val)
-	case float32:
-		return int32(val)
-	case float64:
-		return int32(val)
-	case int64:
-		return int32(val)
-	case int32:
-		return val
-	default:
-		return 0
-	}
+	v, err := m.ToInt32E()
+	m.recordError(err)
+	return v
 }
 
 // ToInt64 returns the data as an int64
 func (m *Data) ToInt64() int64 {
-	switch val := m.value.(type) {
-	case bool:
-		if val {
-			return 1
-		}
-		return 0
-	case string:
-		//convert the string to a int64
-		if val == "" {
-			return 0
-		}
-		i, err := strconv.ParseInt(val, 10, 64)
-		if err != nil {
-			return 0
-		}
-		return i
-	case int:
-		return int64(val)
-	case float32:
-		return int64(val)
-	case float64:
-		return int64(val)
-	case int64:
-		return val
-	default:
-		return 0
-	}
+	v, err := m.ToInt64E()
+	m.recordError(err)
+	return v
+}
+
+// ToUint returns the data as a uint
+func (m *Data) ToUint() uint {
+	v, err := m.ToUintE()
+	m.recordError(err)
+	return v
+}
+
+// ToUint8 returns the data as a uint8
+func (m *Data) ToUint8() uint8 {
+	v, err := m.ToUint8E()
+	m.recordError(err)
+	return v
+}
+
+// ToUint16 returns the data as a uint16
+func (m *Data) ToUint16() uint16 {
+	v, err := m.ToUint16E()
+	m.recordError(err)
+	return v
+}
+
+// ToUint32 returns the data as a uint32
+func (m *Data) ToUint32() uint32 {
+	v, err := m.ToUint32E()
+	m.recordError(err)
+	return v
+}
+
+// ToUint64 returns the data as a uint64
+func (m *Data) ToUint64() uint64 {
+	v, err := m.ToUint64E()
+	m.recordError(err)
+	return v
 }
 
 // ToFloat32 returns the data as a float32
 func (m *Data) ToFloat32() float32 {
-	switch val := m.value.(type) {
-	case bool:
-		if val {
-			return 1
-		}
-		return 0
-	case string:
-		//convert the string to a float32
-		if val == "" {
-			return 0
-		}
-		f, err := strconv.ParseFloat(val, 32)
-		if err != nil {
-			return 0
-		}
-		return float32(f)
-	case int:
-		return float32(val)
-	case float32:
-		return val
-	case float64:
-		return float32(val)
-	default:
-		return 0
-	}
+	v, err := m.ToFloat32E()
+	m.recordError(err)
+	return v
 }
 
 // ToFloat64 returns the data as a float64
 func (m *Data) ToFloat64() float64 {
-	switch val := m.value.(type) {
-	case bool:
-		if val {
-			return 1
-		}
-		return 0
-	case string:
-		//convert the string to a float64
-		if val == "" {
-			return 0
-		}
-		f, err := strconv.ParseFloat(val, 64)
-		if err != nil {
-			return 0
-		}
-		return f
-	case int:
-		return float64(val)
-	case float64:
-		return val
-	default:
-		return 0
-	}
+	v, err := m.ToFloat64E()
+	m.recordError(err)
+	return v
 }
 
 // ToBool returns the data as a bool
 func (m *Data) ToBool() bool {
-	switch val := m.value.(type) {
-	case bool:
-		return val
-	case string:
-		if strings.ToLower(val) == "t" {
-			return true
-		} else if strings.ToLower(val) == "f" {
-			return false
-		} else if strings.ToLower(val) == "yes" {
-			return true
-		} else if strings.ToLower(val) == "no" {
-			return false
-		} else if strings.ToLower(val) == "y" {
-			return true
-		} else if strings.ToLower(val) == "n" {
-			return false
-		} else if strings.ToLower(val) == "1" {
-			return true
-		} else if strings.ToLower(val) == "pass" {
-			return true
-		} else if strings.ToLower(val) == "fail" {
-			return false
-		} else if strings.ToLower(val) == "0" {
-			return false
-		}
-		b, _ := strconv.ParseBool(val)
-		return b
-	case int:
-		return val > 0
-	case int8:
-		return val > 0
-	case int32:
-		return val > 0
-	case int64:
-		return val > 0
-	case float32:
-		return val > 0
-	case float64:
-		return val > 0
-	default:
-		return false
-	}
+	v, err := m.ToBoolE()
+	m.recordError(err)
+	return v
 }
 
 // ToInterface returns the data as an interface{}
 func (m *Data) ToInterface() interface{} {
+	if m == nil {
+		return nil
+	}
 	return m.value
 }
 
 // ToMap returns the data as a map
 func (m *Data) ToMap() map[string]Data {
+	if m == nil {
+		return nil
+	}
 	//check if the value is a map
-	if m.value != nil && reflect.TypeOf(m.value).Kind() == reflect.Map {
+	if values, ok := asMap(m.value); ok {
 		items := make(map[string]Data)
-		for k, o := range m.value.(map[string]interface{}) {
-			items[k] = Data{value: o}
+		for k, o := range values {
+			items[k] = *m.child(o, pathSegment{key: k})
 		}
 		return items
 	}
@@ -295,12 +184,14 @@ func (m *Data) ToMap() map[string]Data {
 // ToArray returns the data as an array
 func (m *Data) ToArray() []Data {
 	var items []Data
-	k := reflect.TypeOf(m.value).Kind()
+	if m == nil {
+		return items
+	}
 	//check if the value is an array
-	if m.value != nil && (k == reflect.Slice || k == reflect.Array) {
+	if values, ok := asArray(m.value); ok {
 		var items []Data
-		for _, o := range m.value.([]interface{}) {
-			items = append(items, Data{value: o})
+		for i, o := range values {
+			items = append(items, *m.child(o, pathSegment{index: i, isIndex: true}))
 		}
 		return items
 	}
@@ -309,78 +200,102 @@ func (m *Data) ToArray() []Data {
 
 // GetMapItem gets a map item by key
 // - Key: the key to get
-// returns a Data object if the key exists or nil if it does not
+// returns a Data object for the key, if the key does not exist the Data object is missing
+// and in safe mode the error is added to Err
 func (m *Data) GetMapItem(key string) *Data {
-	if m.value != nil && reflect.TypeOf(m.value).Kind() == reflect.Map {
-		if m.value.(map[string]interface{})[key] != nil {
-			return &Data{value: m.value.(map[string]interface{})[key], parent: m.parent}
-		} else {
-			if m.parent != nil {
-				//Make so it doesn't panic
-				t_data := m.parent.(*Data)
-				t_data.Err = fmt.Errorf("%v key `%s` does not exist;", m.cleanError(t_data.Err), key)
-				return &Data{value: nil, parent: m.parent}
-			}
-		}
-	} else {
-		if m.parent != nil {
-			//Make so it doesn't panic
-			t_data := m.parent.(*Data)
-			t_data.Err = fmt.Errorf("%vmap with key `%s` does not exist; ", m.cleanError(t_data.Err), key)
-			return &Data{value: nil, parent: m.parent}
+	at := pathSegment{key: key}
+	if !m.Exists() {
+		//the error was added when the item was read
+		return m.missingChild(at)
+	}
+	if value, exists, is_map := mapItem(m.value, key); is_map {
+		//a key set to null exists
+		if exists {
+			return m.child(value, at)
 		}
+		m.addError(m.keyNotFound(key))
+		return m.missingChild(at)
 	}
-	return nil
+	m.addError(m.typeMismatch("map"))
+	return m.missingChild(at)
 }
 
 // GetArrayItem returns an item from the array
 // - index: the index of the item to get
+// returns a Data object for the index, if the index does not exist the Data object is missing
+// and in safe mode the error is added to Err
 func (m *Data) GetArrayItem(index int) *Data {
+	at := pathSegment{index: index, isIndex: true}
+	if !m.Exists() {
+		//the error was added when the item was read
+		return m.missingChild(at)
+	}
 	//check if the value is an array
-	k := reflect.TypeOf(m.value).Kind()
-	if m.value != nil && (k == reflect.Slice || k == reflect.Array) {
-		if len(m.value.([]interface{})) > index {
-			return &Data{value: m.value.([]interface{})[index], parent: m.parent}
-		} else {
-			if m.parent != nil {
-				//Make so it doesn't panic
-				t_data := m.parent.(*Data)
-				t_data.Err = fmt.Errorf("%vindex out of range: `%v`; ", m.cleanError(t_data.Err), index)
-				return &Data{value: nil, parent: m.parent}
-			}
-		}
-	} else {
-		if m.parent != nil {
-			//Make so it doesn't panic
-			t_data := m.parent.(*Data)
-			t_data.Err = fmt.Errorf("%vnot an array: `%v`; ", m.cleanError(t_data.Err), k)
-			return &Data{value: nil, parent: m.parent}
+	if items, ok := asArray(m.value); ok {
+		if index >= 0 && index < len(items) {
+			return m.child(items[index], at)
 		}
+		m.addError(m.indexOutOfRange(index, len(items)))
+		return m.missingChild(at)
 	}
-	return m
+	m.addError(m.typeMismatch("array"))
+	return m.missingChild(at)
 }
 
-// GetArrayCount returns the number of items in the array
+// GetArrayCount returns the number of items in the array, 0 if the data is missing or not an array
 func (m *Data) GetArrayCount() int {
+	if !m.Exists() {
+		return 0
+	}
 	//check if the value is an array
-	if m.value != nil && reflect.TypeOf(m.value).Kind() == reflect.Slice {
-		return len(m.value.([]interface{}))
-	} else {
-		if m.parent != nil {
-			//Make so it doesn't panic
-			t_data := m.parent.(*Data)
-			t_data.Err = fmt.Errorf("%vnot an array; ", m.cleanError(t_data.Err))
-		}
+	if items, ok := asArray(m.value); ok {
+		return len(items)
 	}
+	m.addError(m.typeMismatch("array"))
 	return 0
 }
 
-// cleanError cleans the error
-// - err: the error to clean
-// returns the error as a string
-func (m *Data) cleanError(err error) string {
-	if err != nil {
-		return err.Error()
+// Exists returns false if the data is nil or was read from a key or index that does not exist
+// a key set to null exists
+func (m *Data) Exists() bool {
+	return m != nil && !m.missing
+}
+
+// withOptions returns a copy of the data with changed options
+// items read from the copy inherit its options, so setting an option on the root sets it for the whole chain,
+// errors from the copy are still added to the Err of the root it was made from, so the Err of the copy is always nil
+// - change: the function that changes the options of the copy
+func (m *Data) withOptions(change func(o *options)) *Data {
+	if m == nil {
+		m = m.missingItem()
+	}
+	c := *m
+	c.Err = nil
+	change(&c.opts)
+	return &c
+}
+
+// missingItem creates the Data object returned for an item that does not exist
+// every method can be called on it, conversions return the zero value
+func (m *Data) missingItem() *Data {
+	if m == nil {
+		return &Data{missing: true}
 	}
-	return ""
+	return &Data{value: nil, errSink: m.errSink, opts: m.opts, missing: true}
+}
+
+// missingChild creates the Data object returned for a key or index that does not exist
+// it keeps its location so Path and Parent work and a value set on it is written into the parent
+// - at: the key or index the item was read by
+func (m *Data) missingChild(at pathSegment) *Data {
+	c := m.missingItem()
+	c.up, c.at = m, &at
+	return c
+}
+
+// child creates a Data object for an item reached from this one
+// - value: the value of the item
+// - at: the key or index the item was reached by
+func (m *Data) child(value interface{}, at pathSegment) *Data {
+	return &Data{value: value, errSink: m.errSink, up: m, at: &at, opts: m.opts}
 }
@@ -0,0 +1,223 @@
+package go_data_chain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// UUID is a 128 bit universally unique identifier as defined by RFC 4122
+type UUID [16]byte
+
+// String returns the UUID in its canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func (u UUID) String() string {
+	b := make([]byte, 36)
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b)
+}
+
+// Version returns the version number of the UUID
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// ParseUUID parses a UUID in its canonical form, with braces, with the urn:uuid: prefix or as 32 hex digits
+// - s: the text to parse
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "urn:uuid:")
+	if len(s) == 38 && s[0] == '{' && s[37] == '}' {
+		s = s[1:37]
+	}
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, fmt.Errorf("invalid UUID `%s`", s)
+		}
+		s = s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+	default:
+		return u, fmt.Errorf("invalid UUID length: `%v`", len(s))
+	}
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, fmt.Errorf("invalid UUID `%s`: %v", s, err)
+	}
+	return u, nil
+}
+
+// ToUUID returns the data as a UUID, a value that can not be converted returns the zero UUID
+func (m *Data) ToUUID() UUID {
+	v, err := m.ToUUIDE()
+	m.addError(err)
+	return v
+}
+
+// ToUUIDE returns the data as a UUID
+// strings are read with ParseUUID, byte slices are 16 raw bytes or the UUID as text
+func (m *Data) ToUUIDE() (UUID, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	var s string
+	switch val := m.value.(type) {
+	case UUID:
+		return val, nil
+	case [16]byte:
+		return UUID(val), nil
+	case []byte:
+		if len(val) == 16 {
+			var u UUID
+			copy(u[:], val)
+			return u, nil
+		}
+		s = string(val)
+	case string:
+		s = val
+	default:
+		return UUID{}, m.conversionError("UUID", fmt.Errorf("unsupported type"))
+	}
+	u, err := ParseUUID(s)
+	if err != nil {
+		return UUID{}, m.conversionError("UUID", err)
+	}
+	return u, nil
+}
+
+// semverPattern is the regular expression from the Semantic Versioning 2.0.0 specification
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// Semver is a semantic version as defined by https://semver.org
+type Semver struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease string
+	Build      string
+}
+
+// String returns the version as text without a leading v
+func (v Semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare orders two versions by semver precedence, build metadata is ignored
+// - other: the version to compare with
+// returns -1, 0 or 1 if the version is lower, equal or higher than other
+func (v Semver) Compare(other Semver) int {
+	for _, pair := range [][2]uint64{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	//a version without a prerelease is higher than one with
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+	ids_a, ids_b := strings.Split(v.Prerelease, "."), strings.Split(other.Prerelease, ".")
+	for i := 0; i < len(ids_a) && i < len(ids_b); i++ {
+		if c := comparePrerelease(ids_a[i], ids_b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(ids_a) < len(ids_b):
+		return -1
+	case len(ids_a) > len(ids_b):
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease orders two prerelease identifiers, numbers are lower than text
+func comparePrerelease(a string, b string) int {
+	na, err_a := strconv.ParseUint(a, 10, 64)
+	nb, err_b := strconv.ParseUint(b, 10, 64)
+	switch {
+	case err_a == nil && err_b == nil:
+		if na == nb {
+			return 0
+		}
+		if na < nb {
+			return -1
+		}
+		return 1
+	case err_a == nil:
+		return -1
+	case err_b == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// ParseSemver parses a semantic version, a leading v such as v1.2.3 is allowed
+// - s: the text to parse
+func ParseSemver(s string) (Semver, error) {
+	parts := semverPattern.FindStringSubmatch(strings.TrimPrefix(strings.TrimSpace(s), "v"))
+	if parts == nil {
+		return Semver{}, fmt.Errorf("invalid semantic version `%s`", s)
+	}
+	var v Semver
+	var err error
+	for i, n := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		if *n, err = strconv.ParseUint(parts[i+1], 10, 64); err != nil {
+			return Semver{}, fmt.Errorf("invalid semantic version `%s`: %v", s, err)
+		}
+	}
+	v.Prerelease, v.Build = parts[4], parts[5]
+	return v, nil
+}
+
+// ToSemver returns the data as a semantic version, a value that can not be converted returns the zero Semver
+func (m *Data) ToSemver() Semver {
+	v, err := m.ToSemverE()
+	m.addError(err)
+	return v
+}
+
+// ToSemverE returns the data as a semantic version read with ParseSemver
+func (m *Data) ToSemverE() (Semver, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	var s string
+	switch val := m.value.(type) {
+	case Semver:
+		return val, nil
+	case []byte:
+		s = string(val)
+	case string:
+		s = val
+	default:
+		return Semver{}, m.conversionError("Semver", fmt.Errorf("unsupported type"))
+	}
+	v, err := ParseSemver(s)
+	if err != nil {
+		return Semver{}, m.conversionError("Semver", err)
+	}
+	return v, nil
+}
@@ -0,0 +1,77 @@
+package go_data_chain
+
+import (
+	"testing"
+)
+
+func TestToUUID(t *testing.T) {
+	const canonical = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr bool
+	}{
+		{name: "canonical", value: canonical},
+		{name: "upper_case", value: "F47AC10B-58CC-4372-A567-0E02B2C3D479"},
+		{name: "braces", value: "{" + canonical + "}"},
+		{name: "urn", value: "urn:uuid:" + canonical},
+		{name: "hex_only", value: "f47ac10b58cc4372a5670e02b2c3d479"},
+		{name: "raw_bytes", value: []byte{0xf4, 0x7a, 0xc1, 0x0b, 0x58, 0xcc, 0x43, 0x72, 0xa5, 0x67, 0x0e, 0x02, 0xb2, 0xc3, 0xd4, 0x79}},
+		{name: "bad_dashes", value: "f47ac10b58cc-4372-a567-0e02-b2c3d479", wantErr: true},
+		{name: "bad_hex", value: "g47ac10b-58cc-4372-a567-0e02b2c3d479", wantErr: true},
+		{name: "short", value: "f47ac10b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateDataChain(tt.value, false).ToUUIDE()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToUUIDE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (got.String() != canonical || got.Version() != 4) {
+				t.Errorf("ToUUIDE() = %v version %v, want %v version 4", got, got.Version(), canonical)
+			}
+		})
+	}
+}
+
+func TestToSemver(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    Semver
+		wantErr bool
+	}{
+		{name: "release", value: "1.2.3", want: Semver{Major: 1, Minor: 2, Patch: 3}},
+		{name: "leading_v", value: "v10.0.1", want: Semver{Major: 10, Patch: 1}},
+		{name: "prerelease_build", value: "1.0.0-rc.1+build.5", want: Semver{Major: 1, Prerelease: "rc.1", Build: "build.5"}},
+		{name: "leading_zero", value: "01.2.3", wantErr: true},
+		{name: "missing_patch", value: "1.2", wantErr: true},
+		{name: "number", value: 1.2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateDataChain(tt.value, false).ToSemverE()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToSemverE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToSemverE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	//the order from the semver specification
+	order := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0"}
+	for i := 1; i < len(order); i++ {
+		a, _ := ParseSemver(order[i-1])
+		b, _ := ParseSemver(order[i])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("Compare() %v should be lower than %v", a, b)
+		}
+	}
+	a, _ := ParseSemver("1.0.0+a")
+	b, _ := ParseSemver("1.0.0+b")
+	if a.Compare(b) != 0 {
+		t.Errorf("Compare() should ignore build metadata")
+	}
+}
@@ -0,0 +1,2727 @@
+package go_data_chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JqProgram is a compiled jq program that can be reused with Run
+type JqProgram struct {
+	src  string
+	root jqNode
+}
+
+// CompileJq parses a jq program so it can be reused
+// - src: the program e.g. `.items | map(select(.status == "active") | {name, size})`
+//
+// A subset of jq is supported: paths, iteration, pipes, commas, object and
+// array construction, string interpolation, arithmetic, comparisons, and/or,
+// alternative `//`, if/elif/else, reduce, `as` variable bindings, assignment
+// (`=`, `|=`, `+=`, ...) and the common builtins such as map, select, keys,
+// length, to_entries, from_entries, with_entries, sort_by, group_by, split,
+// join, test, sub, gsub, del and tostring/tonumber/toboolean.
+func CompileJq(src string) (*JqProgram, error) {
+	tokens, err := jqLex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &jqParser{src: src, tokens: tokens}
+	root, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != jqEOF {
+		return nil, p.errorf("unexpected `%s`", p.peek().text)
+	}
+	return &JqProgram{src: src, root: root}, nil
+}
+
+// MustCompileJq is like CompileJq but panics if the program is invalid
+// - src: the program to parse
+func MustCompileJq(src string) *JqProgram {
+	p, err := CompileJq(src)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the source the program was compiled from
+func (p *JqProgram) String() string {
+	return p.src
+}
+
+// Run runs the program against the data
+// - m: the input of the program
+// returns a new Data for every output of the program, nil or missing data is read as null
+func (p *JqProgram) Run(m *Data) ([]*Data, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	outputs, err := p.root.eval(nil, m.value)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*Data, 0, len(outputs))
+	for _, o := range outputs {
+		items = append(items, CreateDataChain(o, m.errSink != nil))
+	}
+	return items, nil
+}
+
+// Jq runs a jq program against the data, see CompileJq
+// - program: the jq program
+// returns a new Data holding the output of the program, an array when the program
+// produces more than one output, the Data object is missing if the data is missing or the program fails
+func (m *Data) Jq(program string) *Data {
+	if !m.Exists() {
+		return m.missingItem()
+	}
+	items, err := m.JqAll(program)
+	if err != nil {
+		m.addError(err)
+		return m.missingItem()
+	}
+	switch len(items) {
+	case 0:
+		return CreateDataChain(nil, m.errSink != nil)
+	case 1:
+		return items[0]
+	}
+	values := make([]interface{}, 0, len(items))
+	for _, o := range items {
+		values = append(values, o.value)
+	}
+	return CreateDataChain(values, m.errSink != nil)
+}
+
+// JqAll runs a jq program against the data and returns every output
+// - program: the jq program
+func (m *Data) JqAll(program string) ([]*Data, error) {
+	p, err := CompileJq(program)
+	if err != nil {
+		return nil, err
+	}
+	return p.Run(m)
+}
+
+//*****
+//Lexer
+//*****
+
+// token kinds
+const (
+	jqEOF = iota
+	jqIdent
+	jqField
+	jqVar
+	jqNumber
+	jqString
+	jqOp
+)
+
+// jqToken is a single token of a program
+type jqToken struct {
+	kind  int
+	text  string
+	value interface{}
+	parts []interface{}
+	pos   int
+}
+
+// jqOps are the operators ordered so the longest match wins
+var jqOps = []string{
+	"//=", "|=", "+=", "-=", "*=", "/=", "%=", "==", "!=", "<=", ">=", "//", "..",
+	"|", ",", "+", "-", "*", "/", "%", "<", ">", "=", "(", ")", "[", "]", "{", "}", ":", ";", "?", ".",
+}
+
+func isJqIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isJqIdentChar(c byte) bool {
+	return isJqIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// jqLex splits a program into tokens
+func jqLex(src string) ([]jqToken, error) {
+	var tokens []jqToken
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			tok, next, err := jqLexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = next
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				i++
+				if i < len(src) && (src[i] == '+' || src[i] == '-') {
+					i++
+				}
+				for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+					i++
+				}
+			}
+			text := src[start:i]
+			var value interface{}
+			if n, err := strconv.Atoi(text); err == nil {
+				value = n
+			} else if f, err := strconv.ParseFloat(text, 64); err == nil {
+				value = f
+			} else {
+				return nil, fmt.Errorf("invalid jq program at %d: invalid number `%s`", start, text)
+			}
+			tokens = append(tokens, jqToken{kind: jqNumber, text: text, value: value, pos: start})
+		case c == '.' && i+1 < len(src) && isJqIdentStart(src[i+1]):
+			start := i
+			i++
+			for i < len(src) && isJqIdentChar(src[i]) {
+				i++
+			}
+			tokens = append(tokens, jqToken{kind: jqField, text: src[start+1 : i], pos: start})
+		case c == '$' && i+1 < len(src) && isJqIdentStart(src[i+1]):
+			start := i
+			i++
+			for i < len(src) && isJqIdentChar(src[i]) {
+				i++
+			}
+			tokens = append(tokens, jqToken{kind: jqVar, text: src[start+1 : i], pos: start})
+		case isJqIdentStart(c):
+			start := i
+			for i < len(src) && isJqIdentChar(src[i]) {
+				i++
+			}
+			tokens = append(tokens, jqToken{kind: jqIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range jqOps {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, jqToken{kind: jqOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("invalid jq program at %d: unexpected `%c`", i, c)
+			}
+		}
+	}
+	return append(tokens, jqToken{kind: jqEOF, pos: len(src)}), nil
+}
+
+// jqLexString reads a string literal, interpolations `\(...)` are compiled into parts
+func jqLexString(src string, pos int) (jqToken, int, error) {
+	var parts []interface{}
+	var sb strings.Builder
+	i := pos + 1
+	for {
+		if i >= len(src) {
+			return jqToken{}, 0, fmt.Errorf("invalid jq program at %d: unterminated string", pos)
+		}
+		c := src[i]
+		if c == '"' {
+			i++
+			break
+		}
+		if c != '\\' {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+		i++
+		if i >= len(src) {
+			return jqToken{}, 0, fmt.Errorf("invalid jq program at %d: unterminated string", pos)
+		}
+		e := src[i]
+		i++
+		switch e {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case '"', '\\', '/':
+			sb.WriteByte(e)
+		case 'u':
+			if i+4 > len(src) {
+				return jqToken{}, 0, fmt.Errorf("invalid jq program at %d: invalid unicode escape", i)
+			}
+			v, err := strconv.ParseUint(src[i:i+4], 16, 32)
+			if err != nil {
+				return jqToken{}, 0, fmt.Errorf("invalid jq program at %d: invalid unicode escape", i)
+			}
+			sb.WriteRune(rune(v))
+			i += 4
+		case '(':
+			//find the matching bracket, skipping nested strings
+			depth, start := 1, i
+			in_string := false
+			for i < len(src) && depth > 0 {
+				switch {
+				case in_string && src[i] == '\\':
+					i++
+				case src[i] == '"':
+					in_string = !in_string
+				case !in_string && src[i] == '(':
+					depth++
+				case !in_string && src[i] == ')':
+					depth--
+				}
+				i++
+			}
+			if depth > 0 {
+				return jqToken{}, 0, fmt.Errorf("invalid jq program at %d: unterminated interpolation", start)
+			}
+			inner, err := CompileJq(src[start : i-1])
+			if err != nil {
+				return jqToken{}, 0, err
+			}
+			if sb.Len() > 0 {
+				parts = append(parts, sb.String())
+				sb.Reset()
+			}
+			parts = append(parts, inner.root)
+		default:
+			return jqToken{}, 0, fmt.Errorf("invalid jq program at %d: invalid escape `\\%c`", i-1, e)
+		}
+	}
+	if sb.Len() > 0 || len(parts) == 0 {
+		parts = append(parts, sb.String())
+	}
+	return jqToken{kind: jqString, text: src[pos:i], parts: parts, pos: pos}, i, nil
+}
+
+//******
+//Parser
+//******
+
+// jqParser holds the state while parsing a program
+type jqParser struct {
+	src    string
+	tokens []jqToken
+	i      int
+}
+
+func (p *jqParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid jq program at %d: %s", p.peek().pos, fmt.Sprintf(format, args...))
+}
+
+func (p *jqParser) peek() jqToken {
+	return p.tokens[p.i]
+}
+
+func (p *jqParser) next() jqToken {
+	tok := p.tokens[p.i]
+	if tok.kind != jqEOF {
+		p.i++
+	}
+	return tok
+}
+
+// isOp returns true if the next token is the operator
+func (p *jqParser) isOp(op string) bool {
+	tok := p.peek()
+	return tok.kind == jqOp && tok.text == op
+}
+
+// isKeyword returns true if the next token is the keyword
+func (p *jqParser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == jqIdent && tok.text == word
+}
+
+func (p *jqParser) consumeOp(op string) bool {
+	if p.isOp(op) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *jqParser) expectOp(op string) error {
+	if !p.consumeOp(op) {
+		return p.errorf("expected `%s`", op)
+	}
+	return nil
+}
+
+func (p *jqParser) expectKeyword(word string) error {
+	if !p.isKeyword(word) {
+		return p.errorf("expected `%s`", word)
+	}
+	p.i++
+	return nil
+}
+
+// bindingAhead returns true if an `as` keyword follows the current token
+func (p *jqParser) bindingAhead() bool {
+	for _, tok := range p.tokens[p.i:] {
+		if tok.kind == jqIdent && tok.text == "as" {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePipe reads `a | b` and `term as $x | body`
+func (p *jqParser) parsePipe() (jqNode, error) {
+	start := p.i
+	if !p.bindingAhead() {
+		//no `as` follows so there is no need to try a binding
+	} else if term, err := p.parsePostfix(); err == nil && p.isKeyword("as") {
+		p.next()
+		tok := p.next()
+		if tok.kind != jqVar {
+			return nil, p.errorf("expected a variable after `as`")
+		}
+		if err := p.expectOp("|"); err != nil {
+			return nil, err
+		}
+		body, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return &jqBind{source: term, name: tok.text, body: body}, nil
+	}
+	p.i = start
+	left, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	if p.consumeOp("|") {
+		right, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return &jqPipe{left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+// parseComma reads `a, b`
+func (p *jqParser) parseComma() (jqNode, error) {
+	left, err := p.parseAlternative()
+	if err != nil {
+		return nil, err
+	}
+	for p.consumeOp(",") {
+		right, err := p.parseAlternative()
+		if err != nil {
+			return nil, err
+		}
+		left = &jqComma{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAlternative reads `a // b`
+func (p *jqParser) parseAlternative() (jqNode, error) {
+	left, err := p.parseAssign()
+	if err != nil {
+		return nil, err
+	}
+	if p.consumeOp("//") {
+		right, err := p.parseAlternative()
+		if err != nil {
+			return nil, err
+		}
+		return &jqAlternative{left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+// parseAssign reads `a = b`, `a |= b` and the arithmetic update operators
+func (p *jqParser) parseAssign() (jqNode, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"=", "|=", "+=", "-=", "*=", "/=", "%=", "//="} {
+		if p.consumeOp(op) {
+			right, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return &jqAssign{op: op, lhs: left, rhs: right}, nil
+		}
+	}
+	return left, nil
+}
+
+// parseOr reads `a or b`
+func (p *jqParser) parseOr() (jqNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &jqBinary{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd reads `a and b`
+func (p *jqParser) parseAnd() (jqNode, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		left = &jqBinary{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseCompare reads a single comparison
+func (p *jqParser) parseCompare() (jqNode, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consumeOp(op) {
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return &jqBinary{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+// parseAdditive reads `a + b` and `a - b`
+func (p *jqParser) parseAdditive() (jqNode, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+") || p.isOp("-") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &jqBinary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseMultiplicative reads `a * b`, `a / b` and `a % b`
+func (p *jqParser) parseMultiplicative() (jqNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*") || p.isOp("/") || p.isOp("%") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &jqBinary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary reads a negated term
+func (p *jqParser) parseUnary() (jqNode, error) {
+	if p.consumeOp("-") {
+		body, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		return &jqNegate{body: body}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix reads a term followed by field access, indexing, iteration and `?`
+func (p *jqParser) parsePostfix() (jqNode, error) {
+	term, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		switch {
+		case tok.kind == jqField:
+			p.next()
+			term = &jqIndex{target: term, key: &jqLiteral{value: tok.text}}
+		case tok.kind == jqOp && tok.text == "." && p.tokens[p.i+1].kind == jqString:
+			p.next()
+			key, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			term = &jqIndex{target: term, key: key}
+		case tok.kind == jqOp && tok.text == "." && p.tokens[p.i+1].kind == jqOp && p.tokens[p.i+1].text == "[":
+			p.next()
+		case tok.kind == jqOp && tok.text == "[":
+			p.next()
+			if term, err = p.parseBracketSuffix(term); err != nil {
+				return nil, err
+			}
+		case tok.kind == jqOp && tok.text == "?":
+			p.next()
+			term = &jqTry{body: term}
+		default:
+			return term, nil
+		}
+	}
+}
+
+// parseBracketSuffix reads `[]`, `[e]` and `[e:e]` after a term
+func (p *jqParser) parseBracketSuffix(term jqNode) (jqNode, error) {
+	if p.consumeOp("]") {
+		return &jqIterate{target: term}, nil
+	}
+	var from jqNode
+	var err error
+	if !p.isOp(":") {
+		if from, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+	}
+	if p.consumeOp(":") {
+		var to jqNode
+		if !p.isOp("]") {
+			if to, err = p.parsePipe(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expectOp("]"); err != nil {
+			return nil, err
+		}
+		return &jqSlice{target: term, from: from, to: to}, nil
+	}
+	if err := p.expectOp("]"); err != nil {
+		return nil, err
+	}
+	return &jqIndex{target: term, key: from}, nil
+}
+
+// parsePrimary reads a single term
+func (p *jqParser) parsePrimary() (jqNode, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case jqNumber:
+		p.next()
+		return &jqLiteral{value: tok.value}, nil
+	case jqString:
+		p.next()
+		if len(tok.parts) == 1 {
+			if s, ok := tok.parts[0].(string); ok {
+				return &jqLiteral{value: s}, nil
+			}
+		}
+		return &jqFormat{parts: tok.parts}, nil
+	case jqField:
+		p.next()
+		return &jqIndex{target: &jqIdentity{}, key: &jqLiteral{value: tok.text}}, nil
+	case jqVar:
+		p.next()
+		return &jqVariable{name: tok.text}, nil
+	case jqIdent:
+		return p.parseIdent()
+	case jqEOF:
+		return nil, p.errorf("unexpected end of program")
+	}
+	switch tok.text {
+	case ".":
+		p.next()
+		if p.peek().kind == jqString {
+			key, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return &jqIndex{target: &jqIdentity{}, key: key}, nil
+		}
+		return &jqIdentity{}, nil
+	case "..":
+		p.next()
+		return &jqRecurse{}, nil
+	case "(":
+		p.next()
+		body, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		return body, nil
+	case "[":
+		p.next()
+		if p.consumeOp("]") {
+			return &jqArray{}, nil
+		}
+		body, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp("]"); err != nil {
+			return nil, err
+		}
+		return &jqArray{body: body}, nil
+	case "{":
+		p.next()
+		return p.parseObject()
+	}
+	return nil, p.errorf("unexpected `%s`", tok.text)
+}
+
+// parseIdent reads keywords, literals and function calls
+func (p *jqParser) parseIdent() (jqNode, error) {
+	tok := p.next()
+	switch tok.text {
+	case "true":
+		return &jqLiteral{value: true}, nil
+	case "false":
+		return &jqLiteral{value: false}, nil
+	case "null":
+		return &jqLiteral{value: nil}, nil
+	case "if":
+		return p.parseIf()
+	case "reduce":
+		source, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("as"); err != nil {
+			return nil, err
+		}
+		v := p.next()
+		if v.kind != jqVar {
+			return nil, p.errorf("expected a variable after `as`")
+		}
+		if err := p.expectOp("("); err != nil {
+			return nil, err
+		}
+		init, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(";"); err != nil {
+			return nil, err
+		}
+		update, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		return &jqReduce{source: source, name: v.text, init: init, update: update}, nil
+	case "then", "elif", "else", "end", "as", "and", "or", "def":
+		p.i--
+		return nil, p.errorf("unexpected `%s`", tok.text)
+	}
+	call := &jqCall{name: tok.text}
+	if p.consumeOp("(") {
+		for {
+			arg, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if p.consumeOp(")") {
+				break
+			}
+			if err := p.expectOp(";"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	arities, ok := jqBuiltins[call.name]
+	if !ok {
+		p.i--
+		return nil, p.errorf("unknown function `%s`", call.name)
+	}
+	for _, n := range arities {
+		if n == len(call.args) {
+			return call, nil
+		}
+	}
+	return nil, p.errorf("function `%s` does not take %d arguments", call.name, len(call.args))
+}
+
+// parseIf reads the rest of an if expression
+func (p *jqParser) parseIf() (jqNode, error) {
+	cond, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("then"); err != nil {
+		return nil, err
+	}
+	then, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	node := &jqIf{cond: cond, then: then}
+	switch {
+	case p.isKeyword("elif"):
+		p.next()
+		if node.otherwise, err = p.parseIf(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case p.isKeyword("else"):
+		p.next()
+		if node.otherwise, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectKeyword("end"); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// parseObject reads the entries of an object construction
+func (p *jqParser) parseObject() (jqNode, error) {
+	obj := &jqObject{}
+	if p.consumeOp("}") {
+		return obj, nil
+	}
+	for {
+		var entry jqEntry
+		tok := p.peek()
+		switch {
+		case tok.kind == jqIdent:
+			p.next()
+			entry.key = &jqLiteral{value: tok.text}
+			entry.value = &jqIndex{target: &jqIdentity{}, key: entry.key}
+		case tok.kind == jqVar:
+			p.next()
+			entry.key = &jqLiteral{value: tok.text}
+			entry.value = &jqVariable{name: tok.text}
+		case tok.kind == jqString:
+			key, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			entry.key = key
+			entry.value = &jqIndex{target: &jqIdentity{}, key: key}
+		case tok.kind == jqOp && tok.text == "(":
+			p.next()
+			key, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			entry.key = key
+		default:
+			return nil, p.errorf("unexpected `%s` in object", tok.text)
+		}
+		if p.consumeOp(":") {
+			value, err := p.parseAlternative()
+			if err != nil {
+				return nil, err
+			}
+			for p.consumeOp("|") {
+				right, err := p.parseAlternative()
+				if err != nil {
+					return nil, err
+				}
+				value = &jqPipe{left: value, right: right}
+			}
+			entry.value = value
+		} else if entry.value == nil {
+			return nil, p.errorf("expected `:`")
+		}
+		obj.entries = append(obj.entries, entry)
+		if p.consumeOp("}") {
+			return obj, nil
+		}
+		if err := p.expectOp(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+//**********
+//Evaluation
+//**********
+
+// jqEnv holds the variables bound with `as` and reduce
+type jqEnv struct {
+	name  string
+	value interface{}
+	next  *jqEnv
+}
+
+// lookup finds a variable
+func (e *jqEnv) lookup(name string) (interface{}, bool) {
+	for ; e != nil; e = e.next {
+		if e.name == name {
+			return e.value, true
+		}
+	}
+	return nil, false
+}
+
+// bind returns a new environment with the variable added
+func (e *jqEnv) bind(name string, value interface{}) *jqEnv {
+	return &jqEnv{name: name, value: value, next: e}
+}
+
+// jqNode is a node of a compiled program
+type jqNode interface {
+	eval(env *jqEnv, input interface{}) ([]interface{}, error)
+}
+
+// jqPather is a node that can be used as a path expression in assignments and del
+type jqPather interface {
+	paths(env *jqEnv, input interface{}) ([][]interface{}, error)
+}
+
+type jqIdentity struct{}
+type jqRecurse struct{}
+type jqLiteral struct{ value interface{} }
+type jqFormat struct{ parts []interface{} }
+type jqVariable struct{ name string }
+type jqIndex struct{ target, key jqNode }
+type jqSlice struct{ target, from, to jqNode }
+type jqIterate struct{ target jqNode }
+type jqTry struct{ body jqNode }
+type jqPipe struct{ left, right jqNode }
+type jqComma struct{ left, right jqNode }
+type jqAlternative struct{ left, right jqNode }
+type jqNegate struct{ body jqNode }
+type jqArray struct{ body jqNode }
+type jqEntry struct{ key, value jqNode }
+type jqObject struct{ entries []jqEntry }
+type jqIf struct{ cond, then, otherwise jqNode }
+type jqBind struct {
+	source jqNode
+	name   string
+	body   jqNode
+}
+type jqReduce struct {
+	source       jqNode
+	name         string
+	init, update jqNode
+}
+type jqBinary struct {
+	op          string
+	left, right jqNode
+}
+type jqAssign struct {
+	op       string
+	lhs, rhs jqNode
+}
+type jqCall struct {
+	name string
+	args []jqNode
+}
+
+func (n *jqIdentity) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	return []interface{}{input}, nil
+}
+
+func (n *jqIdentity) paths(env *jqEnv, input interface{}) ([][]interface{}, error) {
+	return [][]interface{}{{}}, nil
+}
+
+func (n *jqRecurse) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	out := []interface{}{input}
+	for _, c := range jqChildren(input) {
+		more, _ := n.eval(env, c)
+		out = append(out, more...)
+	}
+	return out, nil
+}
+
+func (n *jqRecurse) paths(env *jqEnv, input interface{}) ([][]interface{}, error) {
+	return jqAllPaths(input, []interface{}{}, true), nil
+}
+
+func (n *jqLiteral) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	return []interface{}{n.value}, nil
+}
+
+func (n *jqFormat) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	out := []interface{}{""}
+	for _, part := range n.parts {
+		var values []interface{}
+		if s, ok := part.(string); ok {
+			values = []interface{}{s}
+		} else {
+			var err error
+			if values, err = part.(jqNode).eval(env, input); err != nil {
+				return nil, err
+			}
+		}
+		var next []interface{}
+		for _, prefix := range out {
+			for _, v := range values {
+				next = append(next, prefix.(string)+jqToString(v))
+			}
+		}
+		out = next
+	}
+	return out, nil
+}
+
+func (n *jqVariable) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	v, ok := env.lookup(n.name)
+	if !ok {
+		return nil, fmt.Errorf("$%s is not defined", n.name)
+	}
+	return []interface{}{v}, nil
+}
+
+func (n *jqIndex) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := n.key.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, t := range targets {
+		for _, k := range keys {
+			v, err := jqIndexValue(t, k)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func (n *jqIndex) paths(env *jqEnv, input interface{}) ([][]interface{}, error) {
+	prefixes, err := jqPaths(n.target, env, input)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := n.key.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	var out [][]interface{}
+	for _, prefix := range prefixes {
+		for _, k := range keys {
+			//make sure the path can be indexed
+			if _, err := jqIndexValue(jqGetPath(input, prefix), k); err != nil {
+				return nil, err
+			}
+			out = append(out, jqAppendPath(prefix, k))
+		}
+	}
+	return out, nil
+}
+
+func (n *jqSlice) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	from, to := []interface{}{nil}, []interface{}{nil}
+	if n.from != nil {
+		if from, err = n.from.eval(env, input); err != nil {
+			return nil, err
+		}
+	}
+	if n.to != nil {
+		if to, err = n.to.eval(env, input); err != nil {
+			return nil, err
+		}
+	}
+	var out []interface{}
+	for _, t := range targets {
+		for _, f := range from {
+			for _, e := range to {
+				v, err := jqSliceValue(t, f, e)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, v)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (n *jqIterate) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, t := range targets {
+		if !jqIsContainer(t) {
+			return nil, fmt.Errorf("cannot iterate over %s", jqDescribe(t))
+		}
+		out = append(out, jqChildren(t)...)
+	}
+	return out, nil
+}
+
+func (n *jqIterate) paths(env *jqEnv, input interface{}) ([][]interface{}, error) {
+	prefixes, err := jqPaths(n.target, env, input)
+	if err != nil {
+		return nil, err
+	}
+	var out [][]interface{}
+	for _, prefix := range prefixes {
+		v := jqGetPath(input, prefix)
+		if arr, ok := asArray(v); ok {
+			for i := range arr {
+				out = append(out, jqAppendPath(prefix, i))
+			}
+		} else if obj, ok := asMap(v); ok {
+			for _, k := range sortedKeys(obj) {
+				out = append(out, jqAppendPath(prefix, k))
+			}
+		} else if v != nil {
+			return nil, fmt.Errorf("cannot iterate over %s", jqDescribe(v))
+		}
+	}
+	return out, nil
+}
+
+func (n *jqTry) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	out, err := n.body.eval(env, input)
+	if err != nil {
+		return nil, nil
+	}
+	return out, nil
+}
+
+func (n *jqTry) paths(env *jqEnv, input interface{}) ([][]interface{}, error) {
+	out, err := jqPaths(n.body, env, input)
+	if err != nil {
+		return nil, nil
+	}
+	return out, nil
+}
+
+func (n *jqPipe) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, l := range lefts {
+		rights, err := n.right.eval(env, l)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rights...)
+	}
+	return out, nil
+}
+
+func (n *jqPipe) paths(env *jqEnv, input interface{}) ([][]interface{}, error) {
+	prefixes, err := jqPaths(n.left, env, input)
+	if err != nil {
+		return nil, err
+	}
+	var out [][]interface{}
+	for _, prefix := range prefixes {
+		rest, err := jqPaths(n.right, env, jqGetPath(input, prefix))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rest {
+			out = append(out, append(append([]interface{}{}, prefix...), r...))
+		}
+	}
+	return out, nil
+}
+
+func (n *jqComma) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := n.right.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	return append(lefts, rights...), nil
+}
+
+func (n *jqComma) paths(env *jqEnv, input interface{}) ([][]interface{}, error) {
+	lefts, err := jqPaths(n.left, env, input)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := jqPaths(n.right, env, input)
+	if err != nil {
+		return nil, err
+	}
+	return append(lefts, rights...), nil
+}
+
+func (n *jqAlternative) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(env, input)
+	var out []interface{}
+	if err == nil {
+		for _, l := range lefts {
+			if jqTruthy(l) {
+				out = append(out, l)
+			}
+		}
+	}
+	if len(out) > 0 {
+		return out, nil
+	}
+	return n.right.eval(env, input)
+}
+
+func (n *jqNegate) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	values, err := n.body.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		r, err := jqArithmetic("-", 0, v)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (n *jqArray) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	items := []interface{}{}
+	if n.body != nil {
+		values, err := n.body.eval(env, input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, values...)
+	}
+	return []interface{}{items}, nil
+}
+
+func (n *jqObject) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	out := []interface{}{map[string]interface{}{}}
+	for _, entry := range n.entries {
+		keys, err := entry.key.eval(env, input)
+		if err != nil {
+			return nil, err
+		}
+		values, err := entry.value.eval(env, input)
+		if err != nil {
+			return nil, err
+		}
+		var next []interface{}
+		for _, o := range out {
+			for _, k := range keys {
+				key, ok := k.(string)
+				if !ok {
+					return nil, fmt.Errorf("object keys must be strings, not %s", jqDescribe(k))
+				}
+				for _, v := range values {
+					obj := jqCopyMap(o.(map[string]interface{}))
+					obj[key] = v
+					next = append(next, obj)
+				}
+			}
+		}
+		out = next
+	}
+	return out, nil
+}
+
+func (n *jqIf) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	conds, err := n.cond.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, c := range conds {
+		var values []interface{}
+		if jqTruthy(c) {
+			values, err = n.then.eval(env, input)
+		} else if n.otherwise != nil {
+			values, err = n.otherwise.eval(env, input)
+		} else {
+			values = []interface{}{input}
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, values...)
+	}
+	return out, nil
+}
+
+func (n *jqBind) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	values, err := n.source.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, v := range values {
+		results, err := n.body.eval(env.bind(n.name, v), input)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, results...)
+	}
+	return out, nil
+}
+
+func (n *jqReduce) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	inits, err := n.init.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	values, err := n.source.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, acc := range inits {
+		for _, v := range values {
+			results, err := n.update.eval(env.bind(n.name, v), acc)
+			if err != nil {
+				return nil, err
+			}
+			acc = nil
+			if len(results) > 0 {
+				acc = results[len(results)-1]
+			}
+		}
+		out = append(out, acc)
+	}
+	return out, nil
+}
+
+func (n *jqBinary) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, l := range lefts {
+		if n.op == "and" && !jqTruthy(l) || n.op == "or" && jqTruthy(l) {
+			out = append(out, n.op == "or")
+			continue
+		}
+		rights, err := n.right.eval(env, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rights {
+			var v interface{}
+			switch n.op {
+			case "and", "or":
+				v = jqTruthy(r)
+			case "==":
+				v = deepEqual(l, r)
+			case "!=":
+				v = !deepEqual(l, r)
+			case "<":
+				v = jqCompare(l, r) < 0
+			case "<=":
+				v = jqCompare(l, r) <= 0
+			case ">":
+				v = jqCompare(l, r) > 0
+			case ">=":
+				v = jqCompare(l, r) >= 0
+			default:
+				if v, err = jqArithmetic(n.op, l, r); err != nil {
+					return nil, err
+				}
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func (n *jqAssign) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	paths, err := jqPaths(n.lhs, env, input)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "|=" {
+		out := input
+		for _, path := range paths {
+			results, err := n.rhs.eval(env, jqGetPath(out, path))
+			if err != nil {
+				return nil, err
+			}
+			if len(results) == 0 {
+				if out, err = jqDeletePaths(out, [][]interface{}{path}); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if out, err = jqSetPath(out, path, results[0]); err != nil {
+				return nil, err
+			}
+		}
+		return []interface{}{out}, nil
+	}
+	values, err := n.rhs.eval(env, input)
+	if err != nil {
+		return nil, err
+	}
+	var results []interface{}
+	for _, v := range values {
+		out := input
+		for _, path := range paths {
+			nv := v
+			switch n.op {
+			case "=":
+			case "//=":
+				if old := jqGetPath(out, path); jqTruthy(old) {
+					nv = old
+				}
+			default:
+				if nv, err = jqArithmetic(strings.TrimSuffix(n.op, "="), jqGetPath(out, path), v); err != nil {
+					return nil, err
+				}
+			}
+			if out, err = jqSetPath(out, path, nv); err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, out)
+	}
+	return results, nil
+}
+
+// jqPaths returns the paths a node refers to
+func jqPaths(n jqNode, env *jqEnv, input interface{}) ([][]interface{}, error) {
+	if pather, ok := n.(jqPather); ok {
+		return pather.paths(env, input)
+	}
+	return nil, fmt.Errorf("invalid path expression")
+}
+
+// jqAppendPath copies a path and adds a key to it
+func jqAppendPath(path []interface{}, key interface{}) []interface{} {
+	return append(append(make([]interface{}, 0, len(path)+1), path...), key)
+}
+
+// jqAllPaths returns the paths to every value below the input
+func jqAllPaths(input interface{}, prefix []interface{}, self bool) [][]interface{} {
+	var out [][]interface{}
+	if self {
+		out = append(out, prefix)
+	}
+	if arr, ok := asArray(input); ok {
+		for i, o := range arr {
+			out = append(out, jqAllPaths(o, jqAppendPath(prefix, i), true)...)
+		}
+	} else if obj, ok := asMap(input); ok {
+		for _, k := range sortedKeys(obj) {
+			out = append(out, jqAllPaths(obj[k], jqAppendPath(prefix, k), true)...)
+		}
+	}
+	return out
+}
+
+//*******
+//Helpers
+//*******
+
+// jqTruthy returns false for null and false
+func jqTruthy(v interface{}) bool {
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	return v != nil
+}
+
+// jqIsContainer returns true for arrays and objects
+func jqIsContainer(v interface{}) bool {
+	if _, ok := asArray(v); ok {
+		return true
+	}
+	_, ok := asMap(v)
+	return ok
+}
+
+// jqChildren returns the values of an array or object
+func jqChildren(v interface{}) []interface{} {
+	if arr, ok := asArray(v); ok {
+		return arr
+	}
+	var out []interface{}
+	if obj, ok := asMap(v); ok {
+		for _, k := range sortedKeys(obj) {
+			out = append(out, obj[k])
+		}
+	}
+	return out
+}
+
+// jqType returns the jq type name of a value
+func jqType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	}
+	if _, ok := asNumber(v); ok {
+		return "number"
+	}
+	if _, ok := asArray(v); ok {
+		return "array"
+	}
+	if _, ok := asMap(v); ok {
+		return "object"
+	}
+	return reflect.TypeOf(v).String()
+}
+
+// jqDescribe returns the type and value of a value for error messages
+func jqDescribe(v interface{}) string {
+	text := formatValue(v)
+	if len(text) > 30 {
+		text = text[:27] + "..."
+	}
+	return fmt.Sprintf("%s (%s)", jqType(v), text)
+}
+
+// jqToString converts a value to a string, strings are returned as they are
+// and other scalars use the ToString rules
+func jqToString(v interface{}) string {
+	if jqIsContainer(v) || v == nil {
+		return formatValue(v)
+	}
+	return (&Data{value: v}).ToString()
+}
+
+// jqIsInteger returns true if the value is one of the go integer types
+func jqIsInteger(v interface{}) bool {
+	if n, ok := v.(json.Number); ok {
+		_, err := n.Int64()
+		return err == nil
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// jqMakeNumber returns an int when both operands were integers and the result fits
+func jqMakeNumber(f float64, integer bool) interface{} {
+	if integer && f == math.Trunc(f) && math.Abs(f) <= 1<<53 {
+		return int(f)
+	}
+	return f
+}
+
+// jqCopyMap makes a shallow copy of a map
+func jqCopyMap(obj map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(obj)+1)
+	for k, v := range obj {
+		out[k] = v
+	}
+	return out
+}
+
+// jqArithmetic applies + - * / % to two values
+func jqArithmetic(op string, a interface{}, b interface{}) (interface{}, error) {
+	fa, a_num := asNumber(a)
+	fb, b_num := asNumber(b)
+	if a_num && b_num {
+		integer := jqIsInteger(a) && jqIsInteger(b)
+		switch op {
+		case "+":
+			return jqMakeNumber(fa+fb, integer), nil
+		case "-":
+			return jqMakeNumber(fa-fb, integer), nil
+		case "*":
+			return jqMakeNumber(fa*fb, integer), nil
+		case "/":
+			if fb == 0 {
+				return nil, fmt.Errorf("%s and %s cannot be divided because the divisor is zero", jqDescribe(a), jqDescribe(b))
+			}
+			return jqMakeNumber(fa/fb, integer), nil
+		case "%":
+			ia, ib := (&Data{value: fa}).ToInt(), (&Data{value: fb}).ToInt()
+			if ib == 0 {
+				return nil, fmt.Errorf("%s and %s cannot be divided because the divisor is zero", jqDescribe(a), jqDescribe(b))
+			}
+			return ia % ib, nil
+		}
+	}
+	switch op {
+	case "+":
+		if a == nil {
+			return b, nil
+		}
+		if b == nil {
+			return a, nil
+		}
+		if sa, ok := a.(string); ok {
+			if sb, ok := b.(string); ok {
+				return sa + sb, nil
+			}
+		}
+		if arr_a, ok := asArray(a); ok {
+			if arr_b, ok := asArray(b); ok {
+				return append(append([]interface{}{}, arr_a...), arr_b...), nil
+			}
+		}
+		if obj_a, ok := asMap(a); ok {
+			if obj_b, ok := asMap(b); ok {
+				out := jqCopyMap(obj_a)
+				for k, v := range obj_b {
+					out[k] = v
+				}
+				return out, nil
+			}
+		}
+	case "-":
+		if arr_a, ok := asArray(a); ok {
+			if arr_b, ok := asArray(b); ok {
+				out := []interface{}{}
+				for _, o := range arr_a {
+					found := false
+					for _, r := range arr_b {
+						if deepEqual(o, r) {
+							found = true
+							break
+						}
+					}
+					if !found {
+						out = append(out, o)
+					}
+				}
+				return out, nil
+			}
+		}
+	case "*":
+		if obj_a, ok := asMap(a); ok {
+			if obj_b, ok := asMap(b); ok {
+				return jqDeepMerge(obj_a, obj_b), nil
+			}
+		}
+	case "/":
+		if sa, ok := a.(string); ok {
+			if sb, ok := b.(string); ok {
+				return jqSplit(sa, sb), nil
+			}
+		}
+	}
+	verbs := map[string]string{"+": "added", "-": "subtracted", "*": "multiplied", "/": "divided", "%": "divided"}
+	return nil, fmt.Errorf("%s and %s cannot be %s", jqDescribe(a), jqDescribe(b), verbs[op])
+}
+
+// jqDeepMerge merges two objects recursively
+func jqDeepMerge(a map[string]interface{}, b map[string]interface{}) map[string]interface{} {
+	out := jqCopyMap(a)
+	for k, v := range b {
+		if obj_a, ok := asMap(out[k]); ok {
+			if obj_b, ok := asMap(v); ok {
+				out[k] = jqDeepMerge(obj_a, obj_b)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// jqSplit splits a string into an array of strings
+func jqSplit(s string, sep string) []interface{} {
+	out := []interface{}{}
+	if s == "" {
+		return out
+	}
+	for _, part := range strings.Split(s, sep) {
+		out = append(out, part)
+	}
+	return out
+}
+
+// jqTypeOrder returns the sort order of the jq types
+func jqTypeOrder(v interface{}) int {
+	switch val := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if val {
+			return 2
+		}
+		return 1
+	case string:
+		return 4
+	}
+	if _, ok := asNumber(v); ok {
+		return 3
+	}
+	if _, ok := asArray(v); ok {
+		return 5
+	}
+	return 6
+}
+
+// jqCompare orders two values the way jq does
+func jqCompare(a interface{}, b interface{}) int {
+	ta, tb := jqTypeOrder(a), jqTypeOrder(b)
+	if ta != tb {
+		if ta < tb {
+			return -1
+		}
+		return 1
+	}
+	switch ta {
+	case 3:
+		fa, _ := asNumber(a)
+		fb, _ := asNumber(b)
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	case 4:
+		return strings.Compare(a.(string), b.(string))
+	case 5:
+		arr_a, _ := asArray(a)
+		arr_b, _ := asArray(b)
+		for i := 0; i < len(arr_a) && i < len(arr_b); i++ {
+			if c := jqCompare(arr_a[i], arr_b[i]); c != 0 {
+				return c
+			}
+		}
+		return len(arr_a) - len(arr_b)
+	case 6:
+		obj_a, _ := asMap(a)
+		obj_b, _ := asMap(b)
+		keys_a, keys_b := sortedKeys(obj_a), sortedKeys(obj_b)
+		ka, kb := make([]interface{}, len(keys_a)), make([]interface{}, len(keys_b))
+		for i, k := range keys_a {
+			ka[i] = k
+		}
+		for i, k := range keys_b {
+			kb[i] = k
+		}
+		if c := jqCompare(ka, kb); c != 0 {
+			return c
+		}
+		for _, k := range keys_a {
+			if c := jqCompare(obj_a[k], obj_b[k]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// jqIndexValue returns v[k] for objects, arrays and null
+func jqIndexValue(v interface{}, k interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if obj, ok := asMap(v); ok {
+		if key, ok := k.(string); ok {
+			return obj[key], nil
+		}
+	}
+	if arr, ok := asArray(v); ok {
+		if f, ok := asNumber(k); ok {
+			i := int(math.Floor(f))
+			if i < 0 {
+				i += len(arr)
+			}
+			if i < 0 || i >= len(arr) {
+				return nil, nil
+			}
+			return arr[i], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot index %s with %s", jqType(v), jqDescribe(k))
+}
+
+// jqSliceValue returns v[from:to] for arrays, strings and null
+func jqSliceValue(v interface{}, from interface{}, to interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	length := 0
+	s, is_string := v.(string)
+	arr, is_array := asArray(v)
+	switch {
+	case is_string:
+		length = utf8.RuneCountInString(s)
+	case is_array:
+		length = len(arr)
+	default:
+		return nil, fmt.Errorf("cannot slice %s", jqDescribe(v))
+	}
+	bound := func(b interface{}, def int) (int, error) {
+		if b == nil {
+			return def, nil
+		}
+		f, ok := asNumber(b)
+		if !ok {
+			return 0, fmt.Errorf("start and end indices of a slice must be numbers")
+		}
+		i := int(math.Floor(f))
+		if i < 0 {
+			i += length
+		}
+		if i < 0 {
+			i = 0
+		}
+		if i > length {
+			i = length
+		}
+		return i, nil
+	}
+	start, err := bound(from, 0)
+	if err != nil {
+		return nil, err
+	}
+	end, err := bound(to, length)
+	if err != nil {
+		return nil, err
+	}
+	if end < start {
+		end = start
+	}
+	if is_string {
+		runes := []rune(s)
+		return string(runes[start:end]), nil
+	}
+	return append([]interface{}{}, arr[start:end]...), nil
+}
+
+// jqGetPath returns the value at a path or null
+func jqGetPath(v interface{}, path []interface{}) interface{} {
+	for _, k := range path {
+		var err error
+		if v, err = jqIndexValue(v, k); err != nil {
+			return nil
+		}
+	}
+	return v
+}
+
+// jqSetPath returns a copy of v with the value at the path replaced
+func jqSetPath(v interface{}, path []interface{}, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	switch key := path[0].(type) {
+	case string:
+		obj, ok := asMap(v)
+		if !ok && v != nil {
+			return nil, fmt.Errorf("cannot index %s with \"%s\"", jqType(v), key)
+		}
+		nv, err := jqSetPath(obj[key], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		out := jqCopyMap(obj)
+		out[key] = nv
+		return out, nil
+	default:
+		f, ok := asNumber(key)
+		arr, is_array := asArray(v)
+		if !ok || (!is_array && v != nil) {
+			return nil, fmt.Errorf("cannot index %s with %s", jqType(v), jqDescribe(key))
+		}
+		i := int(f)
+		if i < 0 {
+			i += len(arr)
+			if i < 0 {
+				return nil, fmt.Errorf("out of bounds negative array index")
+			}
+		}
+		out := append([]interface{}{}, arr...)
+		for len(out) <= i {
+			out = append(out, nil)
+		}
+		nv, err := jqSetPath(out[i], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = nv
+		return out, nil
+	}
+}
+
+// jqDeletePaths returns a copy of v with the values at the paths removed
+func jqDeletePaths(v interface{}, paths [][]interface{}) (interface{}, error) {
+	sorted := append([][]interface{}{}, paths...)
+	//delete the last paths first so array indexes stay valid
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return jqCompare(sorted[i], sorted[j]) > 0
+	})
+	var err error
+	for _, path := range sorted {
+		if v, err = jqDeletePath(v, path); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// jqDeletePath returns a copy of v with the value at a path removed
+func jqDeletePath(v interface{}, path []interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	if v == nil {
+		return nil, nil
+	}
+	if len(path) > 1 {
+		child, err := jqIndexValue(v, path[0])
+		if err != nil {
+			return nil, err
+		}
+		nv, err := jqDeletePath(child, path[1:])
+		if err != nil {
+			return nil, err
+		}
+		return jqSetPath(v, path[:1], nv)
+	}
+	if obj, ok := asMap(v); ok {
+		if key, ok := path[0].(string); ok {
+			out := jqCopyMap(obj)
+			delete(out, key)
+			return out, nil
+		}
+	}
+	if arr, ok := asArray(v); ok {
+		if f, ok := asNumber(path[0]); ok {
+			i := int(f)
+			if i < 0 {
+				i += len(arr)
+			}
+			if i < 0 || i >= len(arr) {
+				return v, nil
+			}
+			out := append([]interface{}{}, arr[:i]...)
+			return append(out, arr[i+1:]...), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot delete field at index %s of %s", jqDescribe(path[0]), jqType(v))
+}
+
+//********
+//Builtins
+//********
+
+// jqBuiltins lists the builtin functions and the number of arguments they take
+var jqBuiltins = map[string][]int{
+	"empty": {0}, "not": {0}, "length": {0}, "keys": {0}, "keys_unsorted": {0}, "values": {0},
+	"add": {0}, "type": {0}, "tostring": {0}, "tonumber": {0}, "toboolean": {0}, "tojson": {0},
+	"fromjson": {0}, "ascii_downcase": {0}, "ascii_upcase": {0}, "to_entries": {0},
+	"from_entries": {0}, "sort": {0}, "unique": {0}, "reverse": {0}, "min": {0}, "max": {0},
+	"floor": {0}, "ceil": {0}, "round": {0}, "sqrt": {0}, "abs": {0}, "paths": {0},
+	"recurse": {0, 1}, "any": {0, 1}, "all": {0, 1}, "flatten": {0, 1}, "first": {0, 1},
+	"last": {0, 1}, "error": {0, 1}, "map": {1}, "map_values": {1}, "select": {1}, "has": {1},
+	"contains": {1}, "split": {1}, "join": {1}, "ltrimstr": {1}, "rtrimstr": {1},
+	"startswith": {1}, "endswith": {1}, "test": {1}, "sort_by": {1}, "group_by": {1},
+	"unique_by": {1}, "min_by": {1}, "max_by": {1}, "with_entries": {1}, "del": {1},
+	"path": {1}, "getpath": {1}, "delpaths": {1}, "range": {1, 2}, "setpath": {2},
+	"limit": {2}, "sub": {2}, "gsub": {2},
+}
+
+func (n *jqCall) paths(env *jqEnv, input interface{}) ([][]interface{}, error) {
+	switch n.name {
+	case "empty":
+		return nil, nil
+	case "select":
+		conds, err := n.args[0].eval(env, input)
+		if err != nil {
+			return nil, err
+		}
+		var out [][]interface{}
+		for _, c := range conds {
+			if jqTruthy(c) {
+				out = append(out, []interface{}{})
+			}
+		}
+		return out, nil
+	case "recurse":
+		if len(n.args) == 0 {
+			return jqAllPaths(input, []interface{}{}, true), nil
+		}
+	case "first", "last":
+		if len(n.args) == 1 {
+			all, err := jqPaths(n.args[0], env, input)
+			if err != nil || len(all) == 0 {
+				return nil, err
+			}
+			if n.name == "first" {
+				return all[:1], nil
+			}
+			return all[len(all)-1:], nil
+		}
+	case "getpath":
+		paths, err := n.args[0].eval(env, input)
+		if err != nil {
+			return nil, err
+		}
+		var out [][]interface{}
+		for _, p := range paths {
+			arr, ok := asArray(p)
+			if !ok {
+				return nil, fmt.Errorf("path must be specified as an array")
+			}
+			out = append(out, arr)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("invalid path expression with %s", n.name)
+}
+
+func (n *jqCall) eval(env *jqEnv, input interface{}) ([]interface{}, error) {
+	one := func(v interface{}) ([]interface{}, error) {
+		return []interface{}{v}, nil
+	}
+	//evaluate every combination of the arguments for functions taking values
+	each := func(fn func(args []interface{}) (interface{}, error)) ([]interface{}, error) {
+		combos := [][]interface{}{{}}
+		for _, arg := range n.args {
+			values, err := arg.eval(env, input)
+			if err != nil {
+				return nil, err
+			}
+			var next [][]interface{}
+			for _, c := range combos {
+				for _, v := range values {
+					next = append(next, append(append([]interface{}{}, c...), v))
+				}
+			}
+			combos = next
+		}
+		var out []interface{}
+		for _, c := range combos {
+			v, err := fn(c)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	}
+	str := func(v interface{}, name string) (string, error) {
+		s, ok := v.(string)
+		if !ok {
+			return "", fmt.Errorf("%s cannot be used with %s", jqDescribe(v), name)
+		}
+		return s, nil
+	}
+	arr, is_array := asArray(input)
+	obj, is_object := asMap(input)
+	switch n.name {
+	case "empty":
+		return nil, nil
+	case "not":
+		return one(!jqTruthy(input))
+	case "error":
+		msg := input
+		if len(n.args) == 1 {
+			values, err := n.args[0].eval(env, input)
+			if err != nil {
+				return nil, err
+			}
+			if len(values) > 0 {
+				msg = values[0]
+			}
+		}
+		return nil, fmt.Errorf("%s", jqToString(msg))
+	case "length":
+		switch {
+		case input == nil:
+			return one(0)
+		case is_array:
+			return one(len(arr))
+		case is_object:
+			return one(len(obj))
+		}
+		if s, ok := input.(string); ok {
+			return one(utf8.RuneCountInString(s))
+		}
+		if f, ok := asNumber(input); ok {
+			return one(jqMakeNumber(math.Abs(f), jqIsInteger(input)))
+		}
+		return nil, fmt.Errorf("%s has no length", jqDescribe(input))
+	case "keys", "keys_unsorted":
+		if is_object {
+			out := []interface{}{}
+			for _, k := range sortedKeys(obj) {
+				out = append(out, k)
+			}
+			return one(out)
+		}
+		if is_array {
+			out := []interface{}{}
+			for i := range arr {
+				out = append(out, i)
+			}
+			return one(out)
+		}
+		return nil, fmt.Errorf("%s has no keys", jqDescribe(input))
+	case "values":
+		if input == nil {
+			return nil, nil
+		}
+		return one(input)
+	case "has":
+		return each(func(args []interface{}) (interface{}, error) {
+			if key, ok := args[0].(string); ok && is_object {
+				_, found := obj[key]
+				return found, nil
+			}
+			if f, ok := asNumber(args[0]); ok && is_array {
+				return f >= 0 && int(f) < len(arr), nil
+			}
+			return nil, fmt.Errorf("cannot check whether %s has a %s key", jqType(input), jqType(args[0]))
+		})
+	case "add":
+		if !is_array && !is_object {
+			return nil, fmt.Errorf("cannot add the values of %s", jqDescribe(input))
+		}
+		var acc interface{}
+		for _, v := range jqChildren(input) {
+			var err error
+			if acc, err = jqArithmetic("+", acc, v); err != nil {
+				return nil, err
+			}
+		}
+		return one(acc)
+	case "type":
+		return one(jqType(input))
+	case "tostring":
+		if s, ok := input.(string); ok {
+			return one(s)
+		}
+		return one(jqToString(input))
+	case "tonumber":
+		switch val := input.(type) {
+		case string:
+			text := strings.TrimSpace(val)
+			if _, err := strconv.ParseInt(text, 10, 64); err == nil {
+				return one((&Data{value: text}).ToInt())
+			}
+			if _, err := strconv.ParseFloat(text, 64); err == nil {
+				return one((&Data{value: text}).ToFloat64())
+			}
+			return nil, fmt.Errorf("cannot parse %s as a number", jqDescribe(input))
+		case bool:
+			return one((&Data{value: val}).ToInt())
+		}
+		if _, ok := asNumber(input); ok {
+			return one(input)
+		}
+		return nil, fmt.Errorf("%s cannot be parsed as a number", jqDescribe(input))
+	case "toboolean":
+		return one((&Data{value: input}).ToBool())
+	case "tojson":
+		return one(formatValue(input))
+	case "fromjson":
+		s, err := str(input, n.name)
+		if err != nil {
+			return nil, err
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("%s cannot be parsed as json: %v", jqDescribe(input), err)
+		}
+		return one(v)
+	case "ascii_downcase", "ascii_upcase":
+		s, err := str(input, n.name)
+		if err != nil {
+			return nil, err
+		}
+		if n.name == "ascii_downcase" {
+			return one(strings.ToLower(s))
+		}
+		return one(strings.ToUpper(s))
+	case "to_entries":
+		if !is_object {
+			return nil, fmt.Errorf("%s has no entries", jqDescribe(input))
+		}
+		out := []interface{}{}
+		for _, k := range sortedKeys(obj) {
+			out = append(out, map[string]interface{}{"key": k, "value": obj[k]})
+		}
+		return one(out)
+	case "from_entries":
+		if !is_array {
+			return nil, fmt.Errorf("cannot use %s as entries", jqDescribe(input))
+		}
+		return one(jqFromEntries(arr))
+	case "with_entries":
+		if !is_object {
+			return nil, fmt.Errorf("%s has no entries", jqDescribe(input))
+		}
+		var entries []interface{}
+		for _, k := range sortedKeys(obj) {
+			values, err := n.args[0].eval(env, map[string]interface{}{"key": k, "value": obj[k]})
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, values...)
+		}
+		return one(jqFromEntries(entries))
+	case "sort", "unique", "reverse", "min", "max", "flatten", "any", "all":
+		if input == nil && n.name == "reverse" {
+			return one([]interface{}{})
+		}
+		if s, ok := input.(string); ok && n.name == "reverse" {
+			runes := []rune(s)
+			for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+				runes[i], runes[j] = runes[j], runes[i]
+			}
+			return one(string(runes))
+		}
+		if !is_array {
+			return nil, fmt.Errorf("%s cannot be used with %s", jqDescribe(input), n.name)
+		}
+		return jqArrayBuiltin(n, env, arr)
+	case "sort_by", "group_by", "unique_by", "min_by", "max_by":
+		if !is_array {
+			return nil, fmt.Errorf("%s cannot be used with %s", jqDescribe(input), n.name)
+		}
+		return jqByBuiltin(n, env, arr)
+	case "floor", "ceil", "round", "sqrt", "abs":
+		f, ok := asNumber(input)
+		if !ok {
+			return nil, fmt.Errorf("%s number required", jqDescribe(input))
+		}
+		switch n.name {
+		case "floor":
+			return one(jqMakeNumber(math.Floor(f), true))
+		case "ceil":
+			return one(jqMakeNumber(math.Ceil(f), true))
+		case "round":
+			return one(jqMakeNumber(math.Round(f), true))
+		case "sqrt":
+			return one(math.Sqrt(f))
+		}
+		return one(jqMakeNumber(math.Abs(f), jqIsInteger(input)))
+	case "paths":
+		var out []interface{}
+		for _, p := range jqAllPaths(input, []interface{}{}, false) {
+			out = append(out, p)
+		}
+		return out, nil
+	case "recurse":
+		if len(n.args) == 0 {
+			return (&jqRecurse{}).eval(env, input)
+		}
+		out := []interface{}{input}
+		next, err := n.args[0].eval(env, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range next {
+			more, err := n.eval(env, v)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, more...)
+		}
+		return out, nil
+	case "first", "last":
+		var values []interface{}
+		if len(n.args) == 1 {
+			var err error
+			if values, err = n.args[0].eval(env, input); err != nil {
+				return nil, err
+			}
+		} else if is_array {
+			values = arr
+			if len(values) == 0 {
+				return one(nil)
+			}
+		} else {
+			return nil, fmt.Errorf("cannot index %s with number", jqType(input))
+		}
+		if len(values) == 0 {
+			return nil, nil
+		}
+		if n.name == "first" {
+			return one(values[0])
+		}
+		return one(values[len(values)-1])
+	case "map":
+		if !is_array && !is_object {
+			return nil, fmt.Errorf("cannot iterate over %s", jqDescribe(input))
+		}
+		out := []interface{}{}
+		for _, v := range jqChildren(input) {
+			values, err := n.args[0].eval(env, v)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, values...)
+		}
+		return one(out)
+	case "map_values":
+		if is_array {
+			out := []interface{}{}
+			for _, v := range arr {
+				values, err := n.args[0].eval(env, v)
+				if err != nil {
+					return nil, err
+				}
+				if len(values) > 0 {
+					out = append(out, values[0])
+				}
+			}
+			return one(out)
+		}
+		if is_object {
+			out := map[string]interface{}{}
+			for k, v := range obj {
+				values, err := n.args[0].eval(env, v)
+				if err != nil {
+					return nil, err
+				}
+				if len(values) > 0 {
+					out[k] = values[0]
+				}
+			}
+			return one(out)
+		}
+		return nil, fmt.Errorf("cannot iterate over %s", jqDescribe(input))
+	case "select":
+		conds, err := n.args[0].eval(env, input)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, c := range conds {
+			if jqTruthy(c) {
+				out = append(out, input)
+			}
+		}
+		return out, nil
+	case "contains":
+		return each(func(args []interface{}) (interface{}, error) {
+			if jqType(input) != jqType(args[0]) {
+				return nil, fmt.Errorf("%s and %s cannot have their containment checked", jqDescribe(input), jqDescribe(args[0]))
+			}
+			return jqContains(input, args[0]), nil
+		})
+	case "split", "join", "ltrimstr", "rtrimstr", "startswith", "endswith", "test":
+		return each(func(args []interface{}) (interface{}, error) {
+			if n.name == "join" {
+				if !is_array {
+					return nil, fmt.Errorf("cannot join %s", jqDescribe(input))
+				}
+				sep, err := str(args[0], n.name)
+				if err != nil {
+					return nil, err
+				}
+				parts := make([]string, 0, len(arr))
+				for _, v := range arr {
+					if v == nil {
+						parts = append(parts, "")
+					} else if jqIsContainer(v) {
+						return nil, fmt.Errorf("cannot join with %s", jqDescribe(v))
+					} else {
+						parts = append(parts, jqToString(v))
+					}
+				}
+				return strings.Join(parts, sep), nil
+			}
+			s, is_string := input.(string)
+			arg, arg_is_string := args[0].(string)
+			switch n.name {
+			case "ltrimstr":
+				if is_string && arg_is_string {
+					return strings.TrimPrefix(s, arg), nil
+				}
+				return input, nil
+			case "rtrimstr":
+				if is_string && arg_is_string {
+					return strings.TrimSuffix(s, arg), nil
+				}
+				return input, nil
+			}
+			if !is_string || !arg_is_string {
+				return nil, fmt.Errorf("%s cannot be used with %s", n.name, jqDescribe(input))
+			}
+			switch n.name {
+			case "split":
+				return jqSplit(s, arg), nil
+			case "startswith":
+				return strings.HasPrefix(s, arg), nil
+			case "endswith":
+				return strings.HasSuffix(s, arg), nil
+			}
+			re, err := regexp.Compile(arg)
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a valid regex: %v", arg, err)
+			}
+			return re.MatchString(s), nil
+		})
+	case "sub", "gsub":
+		return each(func(args []interface{}) (interface{}, error) {
+			s, err := str(input, n.name)
+			if err != nil {
+				return nil, err
+			}
+			pattern, err := str(args[0], n.name)
+			if err != nil {
+				return nil, err
+			}
+			replacement, err := str(args[1], n.name)
+			if err != nil {
+				return nil, err
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a valid regex: %v", pattern, err)
+			}
+			if n.name == "gsub" {
+				return re.ReplaceAllLiteralString(s, replacement), nil
+			}
+			done := false
+			return re.ReplaceAllStringFunc(s, func(match string) string {
+				if done {
+					return match
+				}
+				done = true
+				return replacement
+			}), nil
+		})
+	case "del":
+		paths, err := jqPaths(n.args[0], env, input)
+		if err != nil {
+			return nil, err
+		}
+		v, err := jqDeletePaths(input, paths)
+		if err != nil {
+			return nil, err
+		}
+		return one(v)
+	case "path":
+		paths, err := jqPaths(n.args[0], env, input)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, p := range paths {
+			out = append(out, p)
+		}
+		return out, nil
+	case "getpath", "delpaths", "setpath":
+		return each(func(args []interface{}) (interface{}, error) {
+			p, ok := asArray(args[0])
+			if !ok {
+				return nil, fmt.Errorf("path must be specified as an array")
+			}
+			switch n.name {
+			case "getpath":
+				return jqGetPath(input, p), nil
+			case "setpath":
+				return jqSetPath(input, p, args[1])
+			}
+			var paths [][]interface{}
+			for _, o := range p {
+				path, ok := asArray(o)
+				if !ok {
+					return nil, fmt.Errorf("path must be specified as an array")
+				}
+				paths = append(paths, path)
+			}
+			return jqDeletePaths(input, paths)
+		})
+	case "range":
+		bounds, err := each(func(args []interface{}) (interface{}, error) {
+			return args, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, b := range bounds {
+			args := b.([]interface{})
+			from, to := 0.0, 0.0
+			var ok bool
+			if len(args) == 1 {
+				to, ok = asNumber(args[0])
+			} else {
+				if from, ok = asNumber(args[0]); ok {
+					to, ok = asNumber(args[1])
+				}
+			}
+			if !ok {
+				return nil, fmt.Errorf("range bounds must be numeric")
+			}
+			for i := from; i < to; i++ {
+				out = append(out, jqMakeNumber(i, true))
+			}
+		}
+		return out, nil
+	case "limit":
+		counts, err := n.args[0].eval(env, input)
+		if err != nil {
+			return nil, err
+		}
+		values, err := n.args[1].eval(env, input)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, c := range counts {
+			limit := (&Data{value: c}).ToInt()
+			if limit > len(values) {
+				limit = len(values)
+			}
+			if limit > 0 {
+				out = append(out, values[:limit]...)
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%s is not defined", n.name)
+}
+
+// jqArrayBuiltin runs the builtins that work on a whole array
+func jqArrayBuiltin(n *jqCall, env *jqEnv, arr []interface{}) ([]interface{}, error) {
+	switch n.name {
+	case "sort", "unique":
+		out := append([]interface{}{}, arr...)
+		sort.SliceStable(out, func(i, j int) bool { return jqCompare(out[i], out[j]) < 0 })
+		if n.name == "unique" {
+			var deduped []interface{}
+			for i, v := range out {
+				if i == 0 || jqCompare(out[i-1], v) != 0 {
+					deduped = append(deduped, v)
+				}
+			}
+			out = append([]interface{}{}, deduped...)
+		}
+		return []interface{}{out}, nil
+	case "reverse":
+		out := make([]interface{}, len(arr))
+		for i, v := range arr {
+			out[len(arr)-1-i] = v
+		}
+		return []interface{}{out}, nil
+	case "min", "max":
+		if len(arr) == 0 {
+			return []interface{}{nil}, nil
+		}
+		best := arr[0]
+		for _, v := range arr[1:] {
+			c := jqCompare(v, best)
+			if n.name == "min" && c < 0 || n.name == "max" && c >= 0 {
+				best = v
+			}
+		}
+		return []interface{}{best}, nil
+	case "flatten":
+		depth := 1 << 30
+		if len(n.args) == 1 {
+			values, err := n.args[0].eval(env, arr)
+			if err != nil {
+				return nil, err
+			}
+			f, ok := asNumber(values[0])
+			if !ok || f < 0 {
+				return nil, fmt.Errorf("flatten depth must not be negative")
+			}
+			depth = int(f)
+		}
+		return []interface{}{jqFlatten(arr, depth)}, nil
+	case "any", "all":
+		want := n.name == "any"
+		for _, v := range arr {
+			conds := []interface{}{v}
+			if len(n.args) == 1 {
+				var err error
+				if conds, err = n.args[0].eval(env, v); err != nil {
+					return nil, err
+				}
+			}
+			for _, c := range conds {
+				if jqTruthy(c) == want {
+					return []interface{}{want}, nil
+				}
+			}
+		}
+		return []interface{}{!want}, nil
+	}
+	return nil, fmt.Errorf("%s is not defined", n.name)
+}
+
+// jqByBuiltin runs the builtins that order an array by a key expression
+func jqByBuiltin(n *jqCall, env *jqEnv, arr []interface{}) ([]interface{}, error) {
+	type keyed struct {
+		key   interface{}
+		value interface{}
+	}
+	items := make([]keyed, 0, len(arr))
+	for _, v := range arr {
+		keys, err := n.args[0].eval(env, v)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, keyed{key: keys, value: v})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return jqCompare(items[i].key, items[j].key) < 0 })
+	switch n.name {
+	case "min_by", "max_by":
+		if len(items) == 0 {
+			return []interface{}{nil}, nil
+		}
+		if n.name == "min_by" {
+			return []interface{}{items[0].value}, nil
+		}
+		return []interface{}{items[len(items)-1].value}, nil
+	case "sort_by":
+		out := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			out = append(out, item.value)
+		}
+		return []interface{}{out}, nil
+	}
+	out := []interface{}{}
+	for i, item := range items {
+		if i > 0 && jqCompare(items[i-1].key, item.key) == 0 {
+			if n.name == "group_by" {
+				group := out[len(out)-1].([]interface{})
+				out[len(out)-1] = append(group, item.value)
+			}
+			continue
+		}
+		if n.name == "group_by" {
+			out = append(out, []interface{}{item.value})
+		} else {
+			out = append(out, item.value)
+		}
+	}
+	return []interface{}{out}, nil
+}
+
+// jqFromEntries builds an object from {key, value} entries
+func jqFromEntries(entries []interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	for _, e := range entries {
+		entry, ok := asMap(e)
+		if !ok {
+			continue
+		}
+		var key interface{}
+		for _, name := range []string{"key", "k", "name", "Name", "Key", "K"} {
+			if v, ok := entry[name]; ok && v != nil {
+				key = v
+				break
+			}
+		}
+		var value interface{}
+		for _, name := range []string{"value", "v", "Value", "V"} {
+			if v, ok := entry[name]; ok {
+				value = v
+				break
+			}
+		}
+		out[jqToString(key)] = value
+	}
+	return out
+}
+
+// jqFlatten flattens nested arrays up to a depth
+func jqFlatten(arr []interface{}, depth int) []interface{} {
+	out := []interface{}{}
+	for _, v := range arr {
+		if inner, ok := asArray(v); ok && depth > 0 {
+			out = append(out, jqFlatten(inner, depth-1)...)
+		} else {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// jqContains checks if b is contained in a the way jq does
+func jqContains(a interface{}, b interface{}) bool {
+	if sa, ok := a.(string); ok {
+		sb, _ := b.(string)
+		return strings.Contains(sa, sb)
+	}
+	if arr_a, ok := asArray(a); ok {
+		arr_b, _ := asArray(b)
+		for _, vb := range arr_b {
+			found := false
+			for _, va := range arr_a {
+				if jqType(va) == jqType(vb) && jqContains(va, vb) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+	if obj_a, ok := asMap(a); ok {
+		obj_b, _ := asMap(b)
+		for k, vb := range obj_b {
+			va, ok := obj_a[k]
+			if !ok || jqType(va) != jqType(vb) || !jqContains(va, vb) {
+				return false
+			}
+		}
+		return true
+	}
+	return deepEqual(a, b)
+}
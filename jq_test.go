@@ -0,0 +1,189 @@
+package go_data_chain
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestJq(t *testing.T) {
+	var test_data interface{}
+
+	err := yaml.Unmarshal([]byte(jsonpath_test_data), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	tests := []struct {
+		name string
+		args string
+		want interface{}
+	}{
+		{
+			name: "field",
+			args: ".items[0].name",
+			want: "one",
+		},
+		{
+			name: "map_select",
+			args: `.items | map(select(.status == "active") | .name)`,
+			want: []interface{}{"one", "three"},
+		},
+		{
+			name: "object_construction",
+			args: `.items[1] | {title: .name, size, big: (.size > 5)}`,
+			want: map[string]interface{}{"title": "two", "size": 10, "big": true},
+		},
+		{
+			name: "keys_length",
+			args: `[.items[0] | keys, length]`,
+			want: []interface{}{[]interface{}{"name", "size", "status"}, 3},
+		},
+		{
+			name: "to_entries",
+			args: `.items[0] | to_entries | map(.key)`,
+			want: []interface{}{"name", "size", "status"},
+		},
+		{
+			name: "with_entries",
+			args: `.items[0] | with_entries(.key |= ascii_upcase) | keys`,
+			want: []interface{}{"NAME", "SIZE", "STATUS"},
+		},
+		{
+			name: "arithmetic",
+			args: `[.items[].size] | add / length`,
+			want: float64(20) / 3,
+		},
+		{
+			name: "integer_arithmetic",
+			args: `.items[0].size * 2 + 1`,
+			want: 7,
+		},
+		{
+			name: "string_functions",
+			args: `[.items[].name] | join("-") | ascii_upcase | split("-")`,
+			want: []interface{}{"ONE", "TWO", "THREE"},
+		},
+		{
+			name: "interpolation",
+			args: `.items[2] | "\(.name) has \(.size)"`,
+			want: "three has 7",
+		},
+		{
+			name: "reduce",
+			args: `reduce .items[] as $i (0; . + $i.size)`,
+			want: 20,
+		},
+		{
+			name: "variable",
+			args: `.items[0].size as $min | [.items[] | select(.size > $min) | .name]`,
+			want: []interface{}{"two", "three"},
+		},
+		{
+			name: "if",
+			args: `[.items[] | if .size > 5 then "big" elif .size > 2 then "medium" else "small" end]`,
+			want: []interface{}{"medium", "big", "big"},
+		},
+		{
+			name: "alternative",
+			args: `[.items[] | .tags // "none"]`,
+			want: []interface{}{"none", "none", []interface{}{"a", "b"}},
+		},
+		{
+			name: "del",
+			args: `.items[0] | del(.status, .size)`,
+			want: map[string]interface{}{"name": "one"},
+		},
+		{
+			name: "update",
+			args: `.items[0] | .size += 1 | .size`,
+			want: 4,
+		},
+		{
+			name: "sort_by",
+			args: `.items | sort_by(.size) | map(.name)`,
+			want: []interface{}{"one", "three", "two"},
+		},
+		{
+			name: "group_by",
+			args: `.items | group_by(.status) | map(length)`,
+			want: []interface{}{2, 1},
+		},
+		{
+			name: "multiple_outputs",
+			args: `.items[].name`,
+			want: []interface{}{"one", "two", "three"},
+		},
+		{
+			name: "tonumber",
+			args: `"12" | tonumber + 1`,
+			want: 13,
+		},
+		{
+			name: "toboolean",
+			args: `["yes", "no", "pass"] | map(toboolean)`,
+			want: []interface{}{true, false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chain.Jq(tt.args)
+			if got == nil {
+				t.Fatalf("Jq() = nil")
+			}
+			if !reflect.DeepEqual(got.ToInterface(), tt.want) {
+				t.Errorf("Jq() = %#v, want %#v", got.ToInterface(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJqConversion(t *testing.T) {
+	var test_data interface{}
+
+	file, _ := ioutil.ReadFile("examples/example_data.yaml")
+	err := yaml.Unmarshal([]byte(file), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	result := chain.Jq(`.data.convert_float | {total: ((.string_float | tonumber) + .int_float)}`)
+	want := chain.Get("data.convert_float.float_float").ToFloat64() + 5
+	if got := result.GetMapItem("total").ToFloat64(); got != want {
+		t.Errorf("ToFloat64() = %v, want %v", got, want)
+	}
+	if got := chain.GetMapItem("data").GetMapItem("convert_float").GetMapItem("int_float").ToInt(); got != 5 {
+		t.Errorf("Jq() changed the source data, got %v", got)
+	}
+}
+
+func TestCompileJq(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		wantErr bool
+	}{
+		{name: "identity", args: ".", wantErr: false},
+		{name: "unknown_function", args: "foo", wantErr: true},
+		{name: "wrong_arity", args: "map", wantErr: true},
+		{name: "unterminated_string", args: `"abc`, wantErr: true},
+		{name: "unbalanced", args: "(.a", wantErr: true},
+		{name: "missing_end", args: "if . then 1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompileJq(tt.args); (err != nil) != tt.wantErr {
+				t.Errorf("CompileJq() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	chain := CreateDataChain(map[string]interface{}{"a": "text"}, true)
+	if got := chain.Jq(".a + 1"); got == nil || got.ToInterface() != nil || chain.Err == nil {
+		t.Errorf("Jq() = %v, expected an error on the chain", got)
+	}
+	if got := CreateDataChain(map[string]interface{}{}, false).Jq(".a["); got.Exists() {
+		t.Errorf("Jq() = %v, want a missing item", got.ToInterface())
+	}
+}
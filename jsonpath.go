@@ -0,0 +1,964 @@
+package go_data_chain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONPath is a compiled RFC 9535 JSONPath query
+type JSONPath struct {
+	expr  string
+	query *jpQuery
+}
+
+// jpQuery is a list of segments applied to the root or current node
+type jpQuery struct {
+	relative bool
+	segments []jpSegment
+}
+
+// jpSegment is a child or descendant segment with its selectors
+type jpSegment struct {
+	descendant bool
+	selectors  []jpSelector
+}
+
+// selector kinds
+const (
+	jpName = iota
+	jpWildcard
+	jpIndex
+	jpSlice
+	jpFilter
+)
+
+// jpSelector selects children of a node
+type jpSelector struct {
+	kind   int
+	name   string
+	index  int
+	start  *int
+	end    *int
+	step   *int
+	filter jpTest
+}
+
+// jpTest is a logical expression used by filter selectors
+type jpTest interface {
+	test(root *Data, current *Data) bool
+}
+
+// jpOperand is an expression that produces a single value or Nothing
+type jpOperand interface {
+	eval(root *Data, current *Data) (interface{}, bool)
+}
+
+type jpOr struct{ left, right jpTest }
+type jpAnd struct{ left, right jpTest }
+type jpNot struct{ expr jpTest }
+type jpExists struct{ query *jpQuery }
+type jpLiteral struct{ value interface{} }
+type jpCompare struct {
+	op          string
+	left, right jpOperand
+}
+type jpFunction struct {
+	name string
+	args []interface{}
+}
+
+// CompileJSONPath parses an RFC 9535 JSONPath query so it can be reused
+// - expr: the query e.g. `$.items[?@.status == 'active'].name`
+//
+// Supported are name, wildcard, index, slice and filter selectors, unions of
+// selectors, descendant segments and the length, count, match, search and
+// value functions.
+func CompileJSONPath(expr string) (*JSONPath, error) {
+	p := &jpParser{expr: expr}
+	p.skipSpace()
+	if !p.consume("$") {
+		return nil, p.errorf("query must start with `$`")
+	}
+	query, err := p.parseSegments(false)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.expr) {
+		return nil, p.errorf("unexpected `%c`", p.expr[p.pos])
+	}
+	return &JSONPath{expr: expr, query: query}, nil
+}
+
+// MustCompileJSONPath is like CompileJSONPath but panics if the query is invalid
+// - expr: the query to parse
+func MustCompileJSONPath(expr string) *JSONPath {
+	p, err := CompileJSONPath(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the query the JSONPath was compiled from
+func (p *JSONPath) String() string {
+	return p.expr
+}
+
+// Select runs the query against the data
+// - m: the data to use as the root `$` of the query
+// returns the matching items, each knows its location in the data, or nil if the data is missing
+func (p *JSONPath) Select(m *Data) []*Data {
+	if !m.Exists() {
+		return nil
+	}
+	return p.query.selectNodes(m, m)
+}
+
+// Query runs an RFC 9535 JSONPath query against the data
+// - expr: the query, see CompileJSONPath
+// returns the matching items or nil if the query is invalid or the data is missing
+func (m *Data) Query(expr string) []*Data {
+	if !m.Exists() {
+		return nil
+	}
+	p, err := CompileJSONPath(expr)
+	if err != nil {
+		m.addError(err)
+		return nil
+	}
+	return p.Select(m)
+}
+
+// NormalizedPath returns the RFC 9535 normalized path of the data e.g. `$['data'][0]`
+// relative to the value passed to CreateDataChain
+func (m *Data) NormalizedPath() string {
+	var parts []string
+	for item := m; item != nil && item.at != nil; item = item.up {
+		if item.at.isIndex {
+			parts = append(parts, "["+strconv.Itoa(item.at.index)+"]")
+		} else {
+			parts = append(parts, "['"+escapeNormalizedName(item.at.key)+"']")
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("$")
+	for i := len(parts) - 1; i >= 0; i-- {
+		sb.WriteString(parts[i])
+	}
+	return sb.String()
+}
+
+// escapeNormalizedName escapes a member name for a normalized path
+func escapeNormalizedName(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		switch r {
+		case '\'':
+			sb.WriteString(`\'`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				sb.WriteString(fmt.Sprintf(`\u%04x`, r))
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	return sb.String()
+}
+
+//**********
+//Evaluation
+//**********
+
+// selectNodes applies the query to the root or the current node
+func (q *jpQuery) selectNodes(root *Data, current *Data) []*Data {
+	nodes := []*Data{root}
+	if q.relative {
+		nodes = []*Data{current}
+	}
+	for _, seg := range q.segments {
+		var next []*Data
+		for _, node := range nodes {
+			if seg.descendant {
+				for _, d := range descendants(node) {
+					next = seg.apply(root, d, next)
+				}
+			} else {
+				next = seg.apply(root, node, next)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// singular returns true if the query can only ever select one node
+func (q *jpQuery) singular() bool {
+	for _, seg := range q.segments {
+		if seg.descendant || len(seg.selectors) != 1 {
+			return false
+		}
+		if k := seg.selectors[0].kind; k != jpName && k != jpIndex {
+			return false
+		}
+	}
+	return true
+}
+
+// eval returns the value of a singular query
+func (q *jpQuery) eval(root *Data, current *Data) (interface{}, bool) {
+	nodes := q.selectNodes(root, current)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0].value, true
+}
+
+// descendants returns the node and all of its descendants in document order
+func descendants(node *Data) []*Data {
+	nodes := []*Data{node}
+	for _, c := range children(node) {
+		nodes = append(nodes, descendants(c)...)
+	}
+	return nodes
+}
+
+// children returns the items of a map or array
+func children(node *Data) []*Data {
+	var items []*Data
+	if arr, ok := asArray(node.value); ok {
+		for i, o := range arr {
+			items = append(items, node.child(o, pathSegment{index: i, isIndex: true}))
+		}
+	} else if obj, ok := asMap(node.value); ok {
+		for _, k := range sortedKeys(obj) {
+			items = append(items, node.child(obj[k], pathSegment{key: k}))
+		}
+	}
+	return items
+}
+
+// apply runs the selectors of a segment against a node
+func (seg jpSegment) apply(root *Data, node *Data, out []*Data) []*Data {
+	for _, sel := range seg.selectors {
+		switch sel.kind {
+		case jpName:
+			if obj, ok := asMap(node.value); ok {
+				if o, ok := obj[sel.name]; ok {
+					out = append(out, node.child(o, pathSegment{key: sel.name}))
+				}
+			}
+		case jpWildcard:
+			out = append(out, children(node)...)
+		case jpIndex:
+			if arr, ok := asArray(node.value); ok {
+				i := sel.index
+				if i < 0 {
+					i += len(arr)
+				}
+				if i >= 0 && i < len(arr) {
+					out = append(out, node.child(arr[i], pathSegment{index: i, isIndex: true}))
+				}
+			}
+		case jpSlice:
+			if arr, ok := asArray(node.value); ok {
+				for _, i := range sliceIndexes(sel, len(arr)) {
+					out = append(out, node.child(arr[i], pathSegment{index: i, isIndex: true}))
+				}
+			}
+		case jpFilter:
+			for _, c := range children(node) {
+				if sel.filter.test(root, c) {
+					out = append(out, c)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// sliceIndexes returns the indexes selected by a slice selector
+func sliceIndexes(sel jpSelector, length int) []int {
+	step := 1
+	if sel.step != nil {
+		step = *sel.step
+	}
+	if step == 0 {
+		return nil
+	}
+	normalize := func(i int) int {
+		if i < 0 {
+			return length + i
+		}
+		return i
+	}
+	clamp := func(i int, lo int, hi int) int {
+		if i < lo {
+			return lo
+		}
+		if i > hi {
+			return hi
+		}
+		return i
+	}
+	var indexes []int
+	if step > 0 {
+		start, end := 0, length
+		if sel.start != nil {
+			start = normalize(*sel.start)
+		}
+		if sel.end != nil {
+			end = normalize(*sel.end)
+		}
+		lower, upper := clamp(start, 0, length), clamp(end, 0, length)
+		for i := lower; i < upper; i += step {
+			indexes = append(indexes, i)
+		}
+	} else {
+		start, end := length-1, -length-1
+		if sel.start != nil {
+			start = normalize(*sel.start)
+		}
+		if sel.end != nil {
+			end = normalize(*sel.end)
+		}
+		upper, lower := clamp(start, -1, length-1), clamp(end, -1, length-1)
+		for i := upper; lower < i; i += step {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (e jpOr) test(root *Data, current *Data) bool {
+	return e.left.test(root, current) || e.right.test(root, current)
+}
+
+func (e jpAnd) test(root *Data, current *Data) bool {
+	return e.left.test(root, current) && e.right.test(root, current)
+}
+
+func (e jpNot) test(root *Data, current *Data) bool {
+	return !e.expr.test(root, current)
+}
+
+func (e jpExists) test(root *Data, current *Data) bool {
+	return len(e.query.selectNodes(root, current)) > 0
+}
+
+func (e jpLiteral) eval(root *Data, current *Data) (interface{}, bool) {
+	return e.value, true
+}
+
+func (e jpCompare) test(root *Data, current *Data) bool {
+	a, a_ok := e.left.eval(root, current)
+	b, b_ok := e.right.eval(root, current)
+	switch e.op {
+	case "==":
+		return jpEqual(a, a_ok, b, b_ok)
+	case "!=":
+		return !jpEqual(a, a_ok, b, b_ok)
+	case "<":
+		return jpLess(a, a_ok, b, b_ok)
+	case "<=":
+		return jpLess(a, a_ok, b, b_ok) || jpEqual(a, a_ok, b, b_ok)
+	case ">":
+		return jpLess(b, b_ok, a, a_ok)
+	case ">=":
+		return jpLess(b, b_ok, a, a_ok) || jpEqual(a, a_ok, b, b_ok)
+	}
+	return false
+}
+
+// jpEqual compares two values where ok is false for Nothing
+func jpEqual(a interface{}, a_ok bool, b interface{}, b_ok bool) bool {
+	if !a_ok || !b_ok {
+		return !a_ok && !b_ok
+	}
+	return deepEqual(a, b)
+}
+
+// jpLess orders numbers and strings, every other comparison is false
+func jpLess(a interface{}, a_ok bool, b interface{}, b_ok bool) bool {
+	if !a_ok || !b_ok {
+		return false
+	}
+	if fa, ok := asNumber(a); ok {
+		fb, ok := asNumber(b)
+		return ok && fa < fb
+	}
+	sa, ok := a.(string)
+	if !ok {
+		return false
+	}
+	sb, ok := b.(string)
+	return ok && sa < sb
+}
+
+func (e *jpFunction) test(root *Data, current *Data) bool {
+	v, ok := e.eval(root, current)
+	b, _ := v.(bool)
+	return ok && b
+}
+
+func (e *jpFunction) eval(root *Data, current *Data) (interface{}, bool) {
+	switch e.name {
+	case "length":
+		v, ok := e.args[0].(jpOperand).eval(root, current)
+		if !ok {
+			return nil, false
+		}
+		if s, ok := v.(string); ok {
+			return utf8.RuneCountInString(s), true
+		}
+		if items, ok := asArray(v); ok {
+			return len(items), true
+		}
+		if items, ok := asMap(v); ok {
+			return len(items), true
+		}
+		return nil, false
+	case "count":
+		return len(e.args[0].(*jpQuery).selectNodes(root, current)), true
+	case "value":
+		nodes := e.args[0].(*jpQuery).selectNodes(root, current)
+		if len(nodes) != 1 {
+			return nil, false
+		}
+		return nodes[0].value, true
+	case "match", "search":
+		v, v_ok := e.args[0].(jpOperand).eval(root, current)
+		p, p_ok := e.args[1].(jpOperand).eval(root, current)
+		s, s_ok := v.(string)
+		pattern, pattern_ok := p.(string)
+		if !v_ok || !p_ok || !s_ok || !pattern_ok {
+			return false, true
+		}
+		if e.name == "match" {
+			pattern = `^(?:` + pattern + `)$`
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, true
+		}
+		return re.MatchString(s), true
+	}
+	return nil, false
+}
+
+//*******
+//Parsing
+//*******
+
+// jpParser holds the state while parsing a query
+type jpParser struct {
+	expr string
+	pos  int
+}
+
+func (p *jpParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid jsonpath `%s` at %d: %s", p.expr, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *jpParser) skipSpace() {
+	for p.pos < len(p.expr) && strings.IndexByte(" \t\n\r", p.expr[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *jpParser) peek(s string) bool {
+	return strings.HasPrefix(p.expr[p.pos:], s)
+}
+
+func (p *jpParser) consume(s string) bool {
+	if p.peek(s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// parseSegments reads segments until none follow
+func (p *jpParser) parseSegments(relative bool) (*jpQuery, error) {
+	q := &jpQuery{relative: relative}
+	for {
+		start := p.pos
+		p.skipSpace()
+		var seg jpSegment
+		switch {
+		case p.consume(".."):
+			seg.descendant = true
+			if p.peek("[") {
+				sels, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = sels
+			} else {
+				sel, err := p.parseShorthand()
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = []jpSelector{sel}
+			}
+		case p.consume("."):
+			sel, err := p.parseShorthand()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = []jpSelector{sel}
+		case p.peek("["):
+			sels, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = sels
+		default:
+			p.pos = start
+			return q, nil
+		}
+		q.segments = append(q.segments, seg)
+	}
+}
+
+// parseShorthand reads `*` or a member name after a dot
+func (p *jpParser) parseShorthand() (jpSelector, error) {
+	if p.consume("*") {
+		return jpSelector{kind: jpWildcard}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.expr) {
+		r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+		if !(r == '_' || r >= 0x80 || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (p.pos > start && r >= '0' && r <= '9')) {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return jpSelector{}, p.errorf("expected a member name")
+	}
+	return jpSelector{kind: jpName, name: p.expr[start:p.pos]}, nil
+}
+
+// parseBracket reads a comma separated list of selectors in brackets
+func (p *jpParser) parseBracket() ([]jpSelector, error) {
+	p.consume("[")
+	var sels []jpSelector
+	for {
+		p.skipSpace()
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+		p.skipSpace()
+		if p.consume("]") {
+			return sels, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected `,` or `]`")
+		}
+	}
+}
+
+// parseSelector reads a single selector inside brackets
+func (p *jpParser) parseSelector() (jpSelector, error) {
+	switch {
+	case p.peek("'") || p.peek(`"`):
+		name, err := p.parseString()
+		if err != nil {
+			return jpSelector{}, err
+		}
+		return jpSelector{kind: jpName, name: name}, nil
+	case p.consume("*"):
+		return jpSelector{kind: jpWildcard}, nil
+	case p.consume("?"):
+		p.skipSpace()
+		filter, err := p.parseOr()
+		if err != nil {
+			return jpSelector{}, err
+		}
+		return jpSelector{kind: jpFilter, filter: filter}, nil
+	}
+	//index or slice
+	var bounds [3]*int
+	colons := 0
+	for {
+		p.skipSpace()
+		if p.pos < len(p.expr) && (p.expr[p.pos] == '-' || (p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9')) {
+			i, err := p.parseInt()
+			if err != nil {
+				return jpSelector{}, err
+			}
+			bounds[colons] = &i
+			p.skipSpace()
+		}
+		if colons < 2 && p.consume(":") {
+			colons++
+			continue
+		}
+		break
+	}
+	if colons == 0 {
+		if bounds[0] == nil {
+			return jpSelector{}, p.errorf("expected a selector")
+		}
+		return jpSelector{kind: jpIndex, index: *bounds[0]}, nil
+	}
+	return jpSelector{kind: jpSlice, start: bounds[0], end: bounds[1], step: bounds[2]}, nil
+}
+
+// parseInt reads an integer without leading zeros
+func (p *jpParser) parseInt() (int, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+	for p.pos < len(p.expr) && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+		p.pos++
+	}
+	text := p.expr[start:p.pos]
+	if p.pos == digits || (p.pos-digits > 1 && p.expr[digits] == '0') || text == "-0" {
+		return 0, p.errorf("invalid integer `%s`", text)
+	}
+	i, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, p.errorf("invalid integer `%s`", text)
+	}
+	return i, nil
+}
+
+// parseString reads a single or double quoted string literal
+func (p *jpParser) parseString() (string, error) {
+	quote := p.expr[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.expr) {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.expr[p.pos]
+		if c == quote {
+			p.pos++
+			return sb.String(), nil
+		}
+		if c != '\\' {
+			sb.WriteByte(c)
+			p.pos++
+			continue
+		}
+		p.pos++
+		if p.pos >= len(p.expr) {
+			return "", p.errorf("unterminated escape")
+		}
+		e := p.expr[p.pos]
+		p.pos++
+		switch e {
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case '/', '\\', '\'', '"':
+			sb.WriteByte(e)
+		case 'u':
+			r, err := p.parseUnicode()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteRune(r)
+		default:
+			return "", p.errorf("invalid escape `\\%c`", e)
+		}
+	}
+}
+
+// parseUnicode reads the hex digits of a \u escape including surrogate pairs
+func (p *jpParser) parseUnicode() (rune, error) {
+	hex := func() (rune, error) {
+		if p.pos+4 > len(p.expr) {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		v, err := strconv.ParseUint(p.expr[p.pos:p.pos+4], 16, 32)
+		if err != nil {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		p.pos += 4
+		return rune(v), nil
+	}
+	r, err := hex()
+	if err != nil {
+		return 0, err
+	}
+	if r >= 0xD800 && r <= 0xDBFF {
+		if !p.consume(`\u`) {
+			return 0, p.errorf("invalid surrogate pair")
+		}
+		low, err := hex()
+		if err != nil {
+			return 0, err
+		}
+		if low < 0xDC00 || low > 0xDFFF {
+			return 0, p.errorf("invalid surrogate pair")
+		}
+		r = (r-0xD800)<<10 + (low - 0xDC00) + 0x10000
+	}
+	return r, nil
+}
+
+// parseOr reads a logical or expression
+func (p *jpParser) parseOr() (jpTest, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("||") {
+			return left, nil
+		}
+		p.skipSpace()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = jpOr{left: left, right: right}
+	}
+}
+
+// parseAnd reads a logical and expression
+func (p *jpParser) parseAnd() (jpTest, error) {
+	left, err := p.parseBasic()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		p.skipSpace()
+		right, err := p.parseBasic()
+		if err != nil {
+			return nil, err
+		}
+		left = jpAnd{left: left, right: right}
+	}
+}
+
+// parseBasic reads a parenthesized, negated, test or comparison expression
+func (p *jpParser) parseBasic() (jpTest, error) {
+	if p.consume("!") {
+		p.skipSpace()
+		if p.consume("(") {
+			expr, err := p.parseParen()
+			if err != nil {
+				return nil, err
+			}
+			return jpNot{expr: expr}, nil
+		}
+		expr, err := p.parseTestExpr()
+		if err != nil {
+			return nil, err
+		}
+		return jpNot{expr: expr}, nil
+	}
+	if p.consume("(") {
+		return p.parseParen()
+	}
+	start := p.pos
+	left, err := p.parseComparable()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.consume(op) {
+			continue
+		}
+		p.skipSpace()
+		right, err := p.parseComparable()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkComparable(left); err != nil {
+			return nil, err
+		}
+		if err := p.checkComparable(right); err != nil {
+			return nil, err
+		}
+		return jpCompare{op: op, left: left, right: right}, nil
+	}
+	p.pos = start
+	return p.parseTestExpr()
+}
+
+// parseParen reads the rest of a parenthesized expression
+func (p *jpParser) parseParen() (jpTest, error) {
+	p.skipSpace()
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.consume(")") {
+		return nil, p.errorf("expected `)`")
+	}
+	return expr, nil
+}
+
+// parseTestExpr reads an existence test or a logical function
+func (p *jpParser) parseTestExpr() (jpTest, error) {
+	operand, err := p.parseComparable()
+	if err != nil {
+		return nil, err
+	}
+	switch val := operand.(type) {
+	case *jpQuery:
+		return jpExists{query: val}, nil
+	case *jpFunction:
+		if val.name != "match" && val.name != "search" {
+			return nil, p.errorf("function `%s` can not be used as a test", val.name)
+		}
+		return val, nil
+	}
+	return nil, p.errorf("literal can not be used as a test")
+}
+
+// checkComparable makes sure an operand produces a single value
+func (p *jpParser) checkComparable(operand jpOperand) error {
+	switch val := operand.(type) {
+	case *jpQuery:
+		if !val.singular() {
+			return p.errorf("query in comparison must be singular")
+		}
+	case *jpFunction:
+		if val.name == "match" || val.name == "search" {
+			return p.errorf("function `%s` can not be compared", val.name)
+		}
+	}
+	return nil
+}
+
+// parseComparable reads a literal, query or function call
+func (p *jpParser) parseComparable() (jpOperand, error) {
+	if p.pos >= len(p.expr) {
+		return nil, p.errorf("unexpected end of expression")
+	}
+	c := p.expr[p.pos]
+	switch {
+	case c == '@' || c == '$':
+		p.pos++
+		return p.parseSegments(c == '@')
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return jpLiteral{value: s}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case p.consume("true"):
+		return jpLiteral{value: true}, nil
+	case p.consume("false"):
+		return jpLiteral{value: false}, nil
+	case p.consume("null"):
+		return jpLiteral{value: nil}, nil
+	case c >= 'a' && c <= 'z':
+		return p.parseFunction()
+	}
+	return nil, p.errorf("unexpected `%c`", c)
+}
+
+// parseNumber reads a number literal
+func (p *jpParser) parseNumber() (jpOperand, error) {
+	start := p.pos
+	p.consume("-")
+	for p.pos < len(p.expr) && strings.IndexByte("0123456789.eE+-", p.expr[p.pos]) >= 0 {
+		p.pos++
+	}
+	text := p.expr[start:p.pos]
+	if i, err := strconv.Atoi(text); err == nil {
+		return jpLiteral{value: i}, nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, p.errorf("invalid number `%s`", text)
+	}
+	return jpLiteral{value: f}, nil
+}
+
+// parseFunction reads a function call and checks its arguments
+func (p *jpParser) parseFunction() (jpOperand, error) {
+	start := p.pos
+	for p.pos < len(p.expr) && (p.expr[p.pos] == '_' || (p.expr[p.pos] >= 'a' && p.expr[p.pos] <= 'z') || (p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9')) {
+		p.pos++
+	}
+	fn := &jpFunction{name: p.expr[start:p.pos]}
+	arity := map[string]int{"length": 1, "count": 1, "value": 1, "match": 2, "search": 2}
+	n, ok := arity[fn.name]
+	if !ok {
+		return nil, p.errorf("unknown function `%s`", fn.name)
+	}
+	if !p.consume("(") {
+		return nil, p.errorf("expected `(`")
+	}
+	for {
+		p.skipSpace()
+		if len(fn.args) == 0 && p.consume(")") {
+			break
+		}
+		arg, err := p.parseComparable()
+		if err != nil {
+			return nil, err
+		}
+		fn.args = append(fn.args, arg)
+		p.skipSpace()
+		if p.consume(")") {
+			break
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected `,` or `)`")
+		}
+	}
+	if len(fn.args) != n {
+		return nil, p.errorf("function `%s` expects %d arguments", fn.name, n)
+	}
+	for _, arg := range fn.args {
+		query, is_query := arg.(*jpQuery)
+		switch fn.name {
+		case "count", "value":
+			if !is_query {
+				return nil, p.errorf("function `%s` expects a query", fn.name)
+			}
+		default:
+			if is_query && !query.singular() {
+				return nil, p.errorf("function `%s` expects a singular query", fn.name)
+			}
+			if f, ok := arg.(*jpFunction); ok && (f.name == "match" || f.name == "search") {
+				return nil, p.errorf("function `%s` expects a value", fn.name)
+			}
+		}
+	}
+	return fn, nil
+}
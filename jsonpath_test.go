@@ -0,0 +1,205 @@
+package go_data_chain
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const jsonpath_test_data = `
+items:
+  - name: one
+    status: active
+    size: 3
+  - name: two
+    status: inactive
+    size: 10
+  - name: three
+    status: active
+    size: 7
+    tags: [a, b]
+store:
+  book:
+    - title: first
+      price: 8.95
+    - title: second
+      price: 12.99
+`
+
+func TestQuery(t *testing.T) {
+	var test_data interface{}
+
+	err := yaml.Unmarshal([]byte(jsonpath_test_data), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	tests := []struct {
+		name string
+		args string
+		want []interface{}
+	}{
+		{
+			name: "child",
+			args: "$.items[0].name",
+			want: []interface{}{"one"},
+		},
+		{
+			name: "filter",
+			args: "$.items[?@.status == 'active'].name",
+			want: []interface{}{"one", "three"},
+		},
+		{
+			name: "filter_and",
+			args: `$.items[?@.status == "active" && @.size > 5].name`,
+			want: []interface{}{"three"},
+		},
+		{
+			name: "filter_exists",
+			args: "$.items[?@.tags].name",
+			want: []interface{}{"three"},
+		},
+		{
+			name: "filter_not",
+			args: "$.items[?!(@.size < 5)].name",
+			want: []interface{}{"two", "three"},
+		},
+		{
+			name: "wildcard",
+			args: "$.items[*].size",
+			want: []interface{}{3, 10, 7},
+		},
+		{
+			name: "descendant",
+			args: "$..title",
+			want: []interface{}{"first", "second"},
+		},
+		{
+			name: "slice",
+			args: "$.items[::-1].name",
+			want: []interface{}{"three", "two", "one"},
+		},
+		{
+			name: "negative_index",
+			args: "$.items[-1].name",
+			want: []interface{}{"three"},
+		},
+		{
+			name: "union",
+			args: "$.items[0,2]['name']",
+			want: []interface{}{"one", "three"},
+		},
+		{
+			name: "length",
+			args: "$.items[?length(@.tags) == 2].name",
+			want: []interface{}{"three"},
+		},
+		{
+			name: "match",
+			args: "$.items[?match(@.name, 't.*')].name",
+			want: []interface{}{"two", "three"},
+		},
+		{
+			name: "search",
+			args: "$.items[?search(@.name, 'e')].name",
+			want: []interface{}{"one", "three"},
+		},
+		{
+			name: "count",
+			args: "$.items[?count(@.*) == 4].name",
+			want: []interface{}{"three"},
+		},
+		{
+			name: "absolute_in_filter",
+			args: "$.store.book[?@.price < $.items[1].size].title",
+			want: []interface{}{"first"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []interface{}{}
+			for _, item := range chain.Query(tt.args) {
+				got = append(got, item.ToInterface())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryLocation(t *testing.T) {
+	var test_data interface{}
+
+	file, _ := ioutil.ReadFile("examples/example_data.yaml")
+	err := yaml.Unmarshal([]byte(file), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	results := MustCompileJSONPath("$.data.arrays[1]").Select(chain)
+	if len(results) != 1 {
+		t.Fatalf("Select() returned %d results, want 1", len(results))
+	}
+	if got := results[0].ToString(); got != "array_string_2" {
+		t.Errorf("ToString() = %v, want array_string_2", got)
+	}
+	if got := results[0].JSONPointer(); got != "/data/arrays/1" {
+		t.Errorf("JSONPointer() = %v, want /data/arrays/1", got)
+	}
+	if got := results[0].NormalizedPath(); got != "$['data']['arrays'][1]" {
+		t.Errorf("NormalizedPath() = %v, want $['data']['arrays'][1]", got)
+	}
+	results = chain.Query("$.data.convert_bool[?@ == 'yes' || @ == 'y']")
+	for _, item := range results {
+		if !item.ToBool() {
+			t.Errorf("ToBool() = false for %v, want true", item.JSONPointer())
+		}
+	}
+	if len(results) != 2 {
+		t.Errorf("Query() returned %d results, want 2", len(results))
+	}
+}
+
+func TestCompileJSONPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		wantErr bool
+	}{
+		{name: "root", args: "$", wantErr: false},
+		{name: "no_root", args: "a.b", wantErr: true},
+		{name: "unterminated", args: "$[0", wantErr: true},
+		{name: "leading_zero", args: "$[01]", wantErr: true},
+		{name: "non_singular_compare", args: "$[?@.* == 1]", wantErr: true},
+		{name: "length_as_test", args: "$[?length(@)]", wantErr: true},
+		{name: "unknown_function", args: "$[?foo(@)]", wantErr: true},
+		{name: "bad_escape", args: `$['\q']`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompileJSONPath(tt.args); (err != nil) != tt.wantErr {
+				t.Errorf("CompileJSONPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	chain := CreateDataChain(map[string]interface{}{}, true)
+	if got := chain.Query("$["); got != nil || chain.Err == nil {
+		t.Errorf("Query() = %v, expected an error on the chain", got)
+	}
+}
+
+func TestJSONPathLengthOtherShapes(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{
+		"items": []interface{}{
+			[]string{"a", "b"},
+			map[interface{}]interface{}{"x": 1, "y": 2},
+			[]int{1},
+		},
+	}, false)
+	if got := chain.Query("$.items[?length(@) == 2]"); len(got) != 2 {
+		t.Errorf("Query() matched %v items, want the typed slice and the yaml.v2 map", len(got))
+	}
+}
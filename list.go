@@ -0,0 +1,142 @@
+package go_data_chain
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ListOptions configures how the slice converters read values that are not arrays
+type ListOptions struct {
+	//ScalarAsList reads a single value as a list with one item
+	ScalarAsList bool
+	//Separator splits a string into a list, items are trimmed and empty items are dropped
+	Separator string
+}
+
+// WithListOptions returns a copy of the data that reads lists with the options
+// - opts: the list options, the zero value only accepts arrays
+func (m *Data) WithListOptions(opts ListOptions) *Data {
+	return m.withOptions(func(o *options) {
+		o.list = opts
+	})
+}
+
+// listItems returns the items of the data read as a list
+func (m *Data) listItems() ([]*Data, error) {
+	if items, ok := asArray(m.value); ok {
+		list := make([]*Data, len(items))
+		for i, item := range items {
+			list[i] = m.child(item, pathSegment{index: i, isIndex: true})
+		}
+		return list, nil
+	}
+	if s, ok := m.value.(string); ok && m.opts.list.Separator != "" {
+		var list []*Data
+		for _, part := range strings.Split(s, m.opts.list.Separator) {
+			if part = strings.TrimSpace(part); part != "" {
+				list = append(list, m.child(part, pathSegment{index: len(list), isIndex: true}))
+			}
+		}
+		return list, nil
+	}
+	if _, is_map := asMap(m.value); m.value != nil && !is_map && m.opts.list.ScalarAsList {
+		return []*Data{m}, nil
+	}
+	return nil, m.conversionError("list", fmt.Errorf("not an array"))
+}
+
+// ToSliceOf returns the data as a slice converting every item to T
+// items use the same conversion as the To method for T, structs are filled with Decode
+// - m: the data to convert
+func ToSliceOf[T any](m *Data) ([]T, error) {
+	var out []T
+	v, err := m.convertTo(reflect.TypeOf(&out).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface().([]T), nil
+}
+
+// ToMapOf returns the data as a map converting every value to T
+// - m: the data to convert
+func ToMapOf[T any](m *Data) (map[string]T, error) {
+	var out map[string]T
+	v, err := m.convertTo(reflect.TypeOf(&out).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface().(map[string]T), nil
+}
+
+// ToStringSlice returns the data as a []string, a value that can not be converted returns nil
+func (m *Data) ToStringSlice() []string {
+	v, err := m.ToStringSliceE()
+	m.addError(err)
+	return v
+}
+
+// ToStringSliceE returns the data as a []string
+func (m *Data) ToStringSliceE() ([]string, error) {
+	return ToSliceOf[string](m)
+}
+
+// ToIntSlice returns the data as a []int, a value that can not be converted returns nil
+func (m *Data) ToIntSlice() []int {
+	v, err := m.ToIntSliceE()
+	m.addError(err)
+	return v
+}
+
+// ToIntSliceE returns the data as a []int
+func (m *Data) ToIntSliceE() ([]int, error) {
+	return ToSliceOf[int](m)
+}
+
+// ToFloat64Slice returns the data as a []float64, a value that can not be converted returns nil
+func (m *Data) ToFloat64Slice() []float64 {
+	v, err := m.ToFloat64SliceE()
+	m.addError(err)
+	return v
+}
+
+// ToFloat64SliceE returns the data as a []float64
+func (m *Data) ToFloat64SliceE() ([]float64, error) {
+	return ToSliceOf[float64](m)
+}
+
+// ToBoolSlice returns the data as a []bool, a value that can not be converted returns nil
+func (m *Data) ToBoolSlice() []bool {
+	v, err := m.ToBoolSliceE()
+	m.addError(err)
+	return v
+}
+
+// ToBoolSliceE returns the data as a []bool
+func (m *Data) ToBoolSliceE() ([]bool, error) {
+	return ToSliceOf[bool](m)
+}
+
+// ToStringMap returns the data as a map[string]interface{}, a value that can not be converted returns nil
+func (m *Data) ToStringMap() map[string]interface{} {
+	v, err := m.ToStringMapE()
+	m.addError(err)
+	return v
+}
+
+// ToStringMapE returns the data as a map[string]interface{} with the values unchanged
+func (m *Data) ToStringMapE() (map[string]interface{}, error) {
+	return ToMapOf[interface{}](m)
+}
+
+// ToStringMapString returns the data as a map[string]string, a value that can not be converted returns nil
+func (m *Data) ToStringMapString() map[string]string {
+	v, err := m.ToStringMapStringE()
+	m.addError(err)
+	return v
+}
+
+// ToStringMapStringE returns the data as a map[string]string
+func (m *Data) ToStringMapStringE() (map[string]string, error) {
+	return ToMapOf[string](m)
+}
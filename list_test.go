@@ -0,0 +1,85 @@
+package go_data_chain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSliceConverters(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{
+		"hosts":  []interface{}{"web1", "web2"},
+		"ports":  []interface{}{80, "443", 8080.0},
+		"ratios": []float32{0.5, 1},
+		"flags":  []interface{}{true, "false", 1},
+		"csv":    "a, b,,c",
+		"single": "web1",
+		"bad":    []interface{}{1, "two"},
+	}, true)
+	if got := chain.GetMapItem("hosts").ToStringSlice(); !reflect.DeepEqual(got, []string{"web1", "web2"}) {
+		t.Errorf("ToStringSlice() = %v", got)
+	}
+	if got := chain.GetMapItem("ports").ToIntSlice(); !reflect.DeepEqual(got, []int{80, 443, 8080}) {
+		t.Errorf("ToIntSlice() = %v", got)
+	}
+	if got := chain.GetMapItem("ratios").ToFloat64Slice(); !reflect.DeepEqual(got, []float64{0.5, 1}) {
+		t.Errorf("ToFloat64Slice() = %v", got)
+	}
+	if got := chain.GetMapItem("flags").ToBoolSlice(); !reflect.DeepEqual(got, []bool{true, false, true}) {
+		t.Errorf("ToBoolSlice() = %v", got)
+	}
+	if chain.Err != nil {
+		t.Fatalf("Err = %v, want nil", chain.Err)
+	}
+
+	//strings are only lists when configured
+	if got := chain.GetMapItem("csv").ToStringSlice(); got != nil {
+		t.Errorf("ToStringSlice() = %v, want nil", got)
+	}
+	configured := chain.WithListOptions(ListOptions{ScalarAsList: true, Separator: ","})
+	if got := configured.GetMapItem("csv").ToStringSlice(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("ToStringSlice() = %v", got)
+	}
+	if got := configured.WithListOptions(ListOptions{ScalarAsList: true}).GetMapItem("single").ToStringSlice(); !reflect.DeepEqual(got, []string{"web1"}) {
+		t.Errorf("ToStringSlice() = %v", got)
+	}
+
+	_, err := chain.GetMapItem("bad").ToIntSliceE()
+	if err == nil || !strings.Contains(err.Error(), "/bad/1") {
+		t.Errorf("ToIntSliceE() error = %v, want an error for /bad/1", err)
+	}
+}
+
+func TestGenericConverters(t *testing.T) {
+	type limit struct {
+		Max     int
+		Timeout time.Duration
+	}
+	chain := CreateDataChain(map[string]interface{}{
+		"limits":   map[string]interface{}{"cpu": "2", "memory": 512},
+		"timeouts": []interface{}{"1s", "2m"},
+		"services": []interface{}{
+			map[string]interface{}{"max": 10, "timeout": "5s"},
+		},
+		"labels": map[interface{}]interface{}{"app": "web", "tier": 1},
+	}, false)
+	if got, err := ToMapOf[int](chain.GetMapItem("limits")); err != nil || !reflect.DeepEqual(got, map[string]int{"cpu": 2, "memory": 512}) {
+		t.Errorf("ToMapOf[int]() = %v, %v", got, err)
+	}
+	if got, err := ToSliceOf[time.Duration](chain.GetMapItem("timeouts")); err != nil || !reflect.DeepEqual(got, []time.Duration{time.Second, 2 * time.Minute}) {
+		t.Errorf("ToSliceOf[time.Duration]() = %v, %v", got, err)
+	}
+	if got, err := ToSliceOf[limit](chain.GetMapItem("services")); err != nil || !reflect.DeepEqual(got, []limit{{Max: 10, Timeout: 5 * time.Second}}) {
+		t.Errorf("ToSliceOf[limit]() = %v, %v", got, err)
+	}
+	if got := chain.GetMapItem("labels").ToStringMapString(); !reflect.DeepEqual(got, map[string]string{"app": "web", "tier": "1"}) {
+		t.Errorf("ToStringMapString() = %v", got)
+	}
+	if got := chain.GetMapItem("labels").ToStringMap(); !reflect.DeepEqual(got, map[string]interface{}{"app": "web", "tier": 1}) {
+		t.Errorf("ToStringMap() = %v", got)
+	}
+	if _, err := ToMapOf[int](chain.GetMapItem("timeouts")); err == nil {
+		t.Errorf("ToMapOf[int]() of an array should fail")
+	}
+}
@@ -0,0 +1,100 @@
+package go_data_chain
+
+import (
+	"testing"
+)
+
+func TestLocation(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{
+		"data": map[string]interface{}{
+			"items":         []interface{}{map[string]interface{}{"name": "a"}},
+			"key.with.dots": map[string]interface{}{`say "hi"`: 1},
+		},
+	}, true)
+	tests := []struct {
+		name        string
+		item        *Data
+		wantPath    string
+		wantPointer string
+	}{
+		{name: "root", item: chain, wantPath: "", wantPointer: ""},
+		{name: "key", item: chain.GetMapItem("data"), wantPath: "data", wantPointer: "/data"},
+		{name: "index", item: chain.Get("data.items[0].name"), wantPath: "data.items[0].name", wantPointer: "/data/items/0/name"},
+		{name: "quoted", item: chain.Get(`data["key.with.dots"]`), wantPath: `data["key.with.dots"]`, wantPointer: "/data/key.with.dots"},
+		{name: "escaped", item: chain.Get(`data["key.with.dots"]["say \"hi\""]`), wantPath: `data["key.with.dots"]["say \"hi\""]`, wantPointer: `/data/key.with.dots/say "hi"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.item.Path(); got != tt.wantPath {
+				t.Errorf("Path() = %v, want %v", got, tt.wantPath)
+			}
+			if got := tt.item.JSONPointer(); got != tt.wantPointer {
+				t.Errorf("JSONPointer() = %v, want %v", got, tt.wantPointer)
+			}
+			//the path reads the same item back
+			if got := chain.Get(tt.item.Path()); got.JSONPointer() != tt.wantPointer {
+				t.Errorf("Get(Path()) = %v, want %v", got.JSONPointer(), tt.wantPointer)
+			}
+		})
+	}
+
+	name := chain.Get("data.items[0].name")
+	if key, ok := name.Key(); !ok || key != "name" {
+		t.Errorf("Key() = %v, %v, want name", key, ok)
+	}
+	if _, ok := name.Index(); ok {
+		t.Errorf("Index() of a map item should be false")
+	}
+	if index, ok := name.Parent().Index(); !ok || index != 0 {
+		t.Errorf("Parent().Index() = %v, %v, want 0", index, ok)
+	}
+	if got := name.Parent().Parent().Path(); got != "data.items" {
+		t.Errorf("Parent().Parent().Path() = %v, want data.items", got)
+	}
+	if name.Root() != chain || chain.Parent() != nil {
+		t.Errorf("Root() should be the chain and the chain should have no parent")
+	}
+	if _, ok := chain.Key(); ok {
+		t.Errorf("Key() of the root should be false")
+	}
+	if chain.Err != nil {
+		t.Errorf("Err = %v, want nil", chain.Err)
+	}
+}
+
+func TestMissingLocation(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{
+		"data": map[string]interface{}{"items": []interface{}{1}},
+	}, false)
+	tests := []struct {
+		name        string
+		item        *Data
+		wantPath    string
+		wantPointer string
+		wantParent  string
+	}{
+		{name: "key", item: chain.Get("data.other"), wantPath: "data.other", wantPointer: "/data/other", wantParent: "data"},
+		{name: "index", item: chain.Get("data.items[3]"), wantPath: "data.items[3]", wantPointer: "/data/items/3", wantParent: "data.items"},
+		{name: "below_missing", item: chain.Get("data.other.deeper[0]"), wantPath: "data.other.deeper[0]", wantPointer: "/data/other/deeper/0", wantParent: "data.other.deeper"},
+		{name: "pointer", item: chain.Pointer("/data/items/3"), wantPath: "data.items[3]", wantPointer: "/data/items/3", wantParent: "data.items"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.item.Exists() {
+				t.Fatalf("Exists() = true, want a missing item")
+			}
+			if got := tt.item.Path(); got != tt.wantPath {
+				t.Errorf("Path() = %v, want %v", got, tt.wantPath)
+			}
+			if got := tt.item.JSONPointer(); got != tt.wantPointer {
+				t.Errorf("JSONPointer() = %v, want %v", got, tt.wantPointer)
+			}
+			if got := tt.item.Parent().Path(); got != tt.wantParent {
+				t.Errorf("Parent().Path() = %v, want %v", got, tt.wantParent)
+			}
+			if tt.item.Root() != chain {
+				t.Errorf("Root() should be the chain")
+			}
+		})
+	}
+}
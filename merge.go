@@ -0,0 +1,154 @@
+package go_data_chain
+
+import (
+	"fmt"
+)
+
+// MergeStrategy selects how Merge combines two trees
+type MergeStrategy int
+
+const (
+	// MergePatch applies the other tree as an RFC 7386 JSON Merge Patch
+	MergePatch MergeStrategy = iota
+	// DeepMerge merges maps recursively and combines arrays using the ArrayStrategy
+	DeepMerge
+)
+
+// ArrayStrategy selects how DeepMerge combines two arrays
+type ArrayStrategy int
+
+const (
+	// ArrayReplace uses the array from the other tree
+	ArrayReplace ArrayStrategy = iota
+	// ArrayAppend adds the items of the other array to the end
+	ArrayAppend
+	// ArrayMergeByIndex merges the items at the same index
+	ArrayMergeByIndex
+	// ArrayMergeByKey merges map items with the same value in KeyField
+	ArrayMergeByKey
+)
+
+// MergeOptions configures Merge
+type MergeOptions struct {
+	Strategy MergeStrategy
+	Arrays   ArrayStrategy
+	// KeyField is the map key used to match items with ArrayMergeByKey
+	KeyField string
+	// NullDeletes makes a nil value in the other tree remove the key with DeepMerge
+	NullDeletes bool
+}
+
+// Merge combines the data with another tree into a new Data object
+// both of the original trees are left untouched
+// - other: the data to merge on top of this data, nil or a missing item returns a copy of this data
+// and an item set to null replaces it the way RFC 7386 does
+// - opts: the options, the zero value applies other as a JSON Merge Patch
+func (m *Data) Merge(other *Data, opts MergeOptions) *Data {
+	if m == nil {
+		m = m.missingItem()
+	}
+	var value interface{}
+	if !other.Exists() {
+		value = deepCopy(m.value)
+	} else if opts.Strategy == DeepMerge {
+		value = deepMerge(deepCopy(m.value), unwrapValue(other), opts)
+	} else {
+		value = mergePatch(deepCopy(m.value), unwrapValue(other))
+	}
+	return CreateDataChain(value, m.errSink != nil)
+}
+
+// mergePatch applies an RFC 7386 merge patch to the target
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patch_items, ok := asMap(patch)
+	if !ok {
+		return deepCopy(patch)
+	}
+	items, ok := asMap(target)
+	if !ok {
+		items = map[string]interface{}{}
+	}
+	for k, o := range patch_items {
+		if o == nil {
+			delete(items, k)
+		} else {
+			items[k] = mergePatch(items[k], o)
+		}
+	}
+	return items
+}
+
+// deepMerge merges b on top of a
+func deepMerge(a interface{}, b interface{}, opts MergeOptions) interface{} {
+	if items_b, ok := asMap(b); ok {
+		items_a, ok := asMap(a)
+		if !ok {
+			return deepCopy(b)
+		}
+		for k, o := range items_b {
+			if o == nil && opts.NullDeletes {
+				delete(items_a, k)
+			} else if existing, ok := items_a[k]; ok {
+				items_a[k] = deepMerge(existing, o, opts)
+			} else {
+				items_a[k] = deepCopy(o)
+			}
+		}
+		return items_a
+	}
+	if items_b, ok := asArray(b); ok {
+		if items_a, ok := asArray(a); ok {
+			return mergeArrays(items_a, items_b, opts)
+		}
+	}
+	return deepCopy(b)
+}
+
+// mergeArrays combines two arrays using the ArrayStrategy
+func mergeArrays(a []interface{}, b []interface{}, opts MergeOptions) []interface{} {
+	switch opts.Arrays {
+	case ArrayAppend:
+		return append(a, deepCopy(b).([]interface{})...)
+	case ArrayMergeByIndex:
+		for i, o := range b {
+			if i < len(a) {
+				a[i] = deepMerge(a[i], o, opts)
+			} else {
+				a = append(a, deepCopy(o))
+			}
+		}
+		return a
+	case ArrayMergeByKey:
+		positions := map[string]int{}
+		for i, o := range a {
+			if key, ok := mergeKey(o, opts.KeyField); ok {
+				positions[key] = i
+			}
+		}
+		for _, o := range b {
+			if key, ok := mergeKey(o, opts.KeyField); ok {
+				if i, found := positions[key]; found {
+					a[i] = deepMerge(a[i], o, opts)
+					continue
+				}
+				positions[key] = len(a)
+			}
+			a = append(a, deepCopy(o))
+		}
+		return a
+	}
+	return deepCopy(b).([]interface{})
+}
+
+// mergeKey returns the value of the key field of a map item as a string
+func mergeKey(item interface{}, field string) (string, bool) {
+	items, ok := asMap(item)
+	if !ok {
+		return "", false
+	}
+	o, ok := items[field]
+	if !ok || o == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%T:%v", o, o), true
+}
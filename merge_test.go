@@ -0,0 +1,160 @@
+package go_data_chain
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const merge_base_data = `
+server:
+  host: localhost
+  port: 8080
+  tags: [a, b]
+users:
+  - name: alice
+    role: admin
+  - name: bob
+    role: user
+`
+
+const merge_override_data = `
+server:
+  port: 9090
+  host: null
+  tags: [c]
+users:
+  - name: bob
+    role: admin
+  - name: carol
+    role: user
+`
+
+func TestMerge(t *testing.T) {
+	var base, override interface{}
+	if err := yaml.Unmarshal([]byte(merge_base_data), &base); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal([]byte(merge_override_data), &override); err != nil {
+		t.Fatal(err)
+	}
+	chain := CreateDataChain(base, false)
+	other := CreateDataChain(override, false)
+	tests := []struct {
+		name string
+		opts MergeOptions
+		path string
+		want interface{}
+	}{
+		{
+			name: "merge_patch_null_deletes",
+			opts: MergeOptions{},
+			path: "server",
+			want: map[string]interface{}{"port": 9090, "tags": []interface{}{"c"}},
+		},
+		{
+			name: "deep_merge_null_overrides",
+			opts: MergeOptions{Strategy: DeepMerge},
+			path: "server",
+			want: map[string]interface{}{"host": nil, "port": 9090, "tags": []interface{}{"c"}},
+		},
+		{
+			name: "deep_merge_null_deletes",
+			opts: MergeOptions{Strategy: DeepMerge, NullDeletes: true},
+			path: "server",
+			want: map[string]interface{}{"port": 9090, "tags": []interface{}{"c"}},
+		},
+		{
+			name: "array_append",
+			opts: MergeOptions{Strategy: DeepMerge, Arrays: ArrayAppend},
+			path: "server.tags",
+			want: []interface{}{"a", "b", "c"},
+		},
+		{
+			name: "array_merge_by_index",
+			opts: MergeOptions{Strategy: DeepMerge, Arrays: ArrayMergeByIndex},
+			path: "users",
+			want: []interface{}{
+				map[string]interface{}{"name": "bob", "role": "admin"},
+				map[string]interface{}{"name": "carol", "role": "user"},
+			},
+		},
+		{
+			name: "array_merge_by_key",
+			opts: MergeOptions{Strategy: DeepMerge, Arrays: ArrayMergeByKey, KeyField: "name"},
+			path: "users",
+			want: []interface{}{
+				map[string]interface{}{"name": "alice", "role": "admin"},
+				map[string]interface{}{"name": "bob", "role": "admin"},
+				map[string]interface{}{"name": "carol", "role": "user"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chain.Merge(other, tt.opts)
+			if !reflect.DeepEqual(got.Get(tt.path).ToInterface(), tt.want) {
+				t.Errorf("Merge() = %v, want %v", got.Get(tt.path).ToInterface(), tt.want)
+			}
+		})
+	}
+	//the original trees must be untouched
+	if got := chain.Get("server.host").ToString(); got != "localhost" {
+		t.Errorf("Merge() changed the base data, host = %v", got)
+	}
+	if got := chain.Get("server.tags").GetArrayCount(); got != 2 {
+		t.Errorf("Merge() changed the base data, tags = %v", got)
+	}
+	if got := other.Get("users").GetArrayCount(); got != 2 {
+		t.Errorf("Merge() changed the other data, users = %v", got)
+	}
+}
+
+func TestMergePatchRFC7386(t *testing.T) {
+	tests := []struct {
+		name   string
+		target interface{}
+		patch  interface{}
+		want   interface{}
+	}{
+		{name: "replace", target: map[string]interface{}{"a": "b"}, patch: map[string]interface{}{"a": "c"}, want: map[string]interface{}{"a": "c"}},
+		{name: "add", target: map[string]interface{}{"a": "b"}, patch: map[string]interface{}{"b": "c"}, want: map[string]interface{}{"a": "b", "b": "c"}},
+		{name: "delete", target: map[string]interface{}{"a": "b", "b": "c"}, patch: map[string]interface{}{"a": nil}, want: map[string]interface{}{"b": "c"}},
+		{name: "array", target: map[string]interface{}{"a": []interface{}{"b"}}, patch: map[string]interface{}{"a": "c"}, want: map[string]interface{}{"a": "c"}},
+		{name: "non_object", target: map[string]interface{}{"a": "b"}, patch: []interface{}{"c"}, want: []interface{}{"c"}},
+		{name: "into_scalar", target: "x", patch: map[string]interface{}{"a": map[string]interface{}{"b": nil}}, want: map[string]interface{}{"a": map[string]interface{}{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreateDataChain(tt.target, false).Merge(CreateDataChain(tt.patch, false), MergeOptions{})
+			if !reflect.DeepEqual(got.ToInterface(), tt.want) {
+				t.Errorf("Merge() = %v, want %v", got.ToInterface(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeMissing(t *testing.T) {
+	base := CreateDataChain(map[string]interface{}{"a": "b"}, false)
+	overrides := CreateDataChain(map[string]interface{}{"dev": map[string]interface{}{"a": "c"}}, false)
+	tests := []struct {
+		name  string
+		other *Data
+		want  interface{}
+	}{
+		{name: "missing", other: overrides.GetMapItem("prod"), want: map[string]interface{}{"a": "b"}},
+		{name: "nil", other: nil, want: map[string]interface{}{"a": "b"}},
+		{name: "null", other: CreateDataChain(nil, false), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, strategy := range []MergeStrategy{MergePatch, DeepMerge} {
+				got := base.Merge(tt.other, MergeOptions{Strategy: strategy})
+				if !reflect.DeepEqual(got.ToInterface(), tt.want) {
+					t.Errorf("Merge() = %v, want %v", got.ToInterface(), tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package go_data_chain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMissingItems(t *testing.T) {
+	value := map[string]interface{}{
+		"data": map[string]interface{}{"items": []interface{}{1, 2}, "name": "web", "empty": nil},
+	}
+	for _, safe := range []bool{false, true} {
+		chain := CreateDataChain(value, safe)
+		tests := []struct {
+			name string
+			item *Data
+		}{
+			{name: "key", item: chain.GetMapItem("other").GetMapItem("deeper")},
+			{name: "index", item: chain.GetMapItem("data").GetMapItem("items").GetArrayItem(5).GetArrayItem(0)},
+			{name: "negative_index", item: chain.Get("data.items").GetArrayItem(-1)},
+			{name: "not_an_array", item: chain.Get("data.name").GetArrayItem(0)},
+			{name: "path", item: chain.Get("data.other[0].name")},
+			{name: "pointer", item: chain.Pointer("/data/items/9")},
+			{name: "jq", item: chain.GetMapItem("other").Jq(".a")},
+			{name: "nil", item: (*Data)(nil).GetMapItem("a")},
+			{name: "nil_data", item: nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.item.Exists() {
+					t.Fatalf("Exists() = true, want a missing item")
+				}
+				if tt.item.ToString() != "" || tt.item.ToInt() != 0 || tt.item.ToFloat64() != 0 || tt.item.ToBool() || tt.item.ToInterface() != nil {
+					t.Errorf("conversions of a missing item should be the zero value")
+				}
+				if _, err := tt.item.ToIntE(); err == nil {
+					t.Errorf("ToIntE() expected an error for a missing item")
+				}
+				if _, err := tt.item.ToTimeE(); err == nil {
+					t.Errorf("ToTimeE() expected an error for a missing item")
+				}
+				var target struct{ Name string }
+				if err := tt.item.Decode(&target); err != nil || target.Name != "" {
+					t.Errorf("Decode() = %v, %v, want the target left empty", target, err)
+				}
+				if err := tt.item.SetArrayItem(0, 1); err == nil {
+					t.Errorf("SetArrayItem() expected an error for a missing item")
+				}
+				if tt.item.ToMap() != nil || len(tt.item.ToArray()) != 0 || tt.item.GetArrayCount() != 0 {
+					t.Errorf("a missing item should have no items")
+				}
+				if tt.item.GetType() != "invalid" || tt.item.Query("$.a") != nil {
+					t.Errorf("GetType() = %v, want invalid", tt.item.GetType())
+				}
+			})
+		}
+		if null := chain.Get("data.empty"); !null.Exists() || null.ToInterface() != nil {
+			t.Errorf("a key set to null should exist")
+		}
+		if !safe && chain.Err != nil {
+			t.Errorf("Err = %v, want nil when not safe", chain.Err)
+		}
+	}
+}
+
+func TestMissingItemErrors(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{"data": map[string]interface{}{}}, true)
+	chain.Get("data.a.b.c[0]").ToURL()
+	var multi *MultiError
+	if !errors.As(chain.Err, &multi) || len(multi.Errors) != 1 {
+		t.Fatalf("Err = %v, want only the error for the first missing key", chain.Err)
+	}
+	var not_found *KeyNotFoundError
+	if !errors.As(chain.Err, &not_found) || not_found.Path != "/data/a" {
+		t.Errorf("errors.As() KeyNotFoundError = %v, want /data/a", not_found)
+	}
+	var nil_data *Data
+	if nil_data.Exists() || nil_data.GetType() != "invalid" || nil_data.Get("a").Exists() || nil_data.FirstOf("a").Exists() {
+		t.Errorf("a nil Data should read as missing")
+	}
+	if nil_data.Parent() != nil || nil_data.Root() != nil || nil_data.Path() != "" {
+		t.Errorf("a nil Data should have no location")
+	}
+	if _, ok := nil_data.Key(); ok {
+		t.Errorf("Key() of a nil Data should be false")
+	}
+	if err := nil_data.SetMapItem("a", 1); err == nil {
+		t.Errorf("SetMapItem() expected an error for a nil Data")
+	}
+	if items, err := nil_data.JqAll("."); err != nil || len(items) != 1 || items[0].ToInterface() != nil {
+		t.Errorf("JqAll() = %v, %v, want a single null", items, err)
+	}
+	if items, err := MustCompileJq(".").Run(nil_data); err != nil || len(items) != 1 {
+		t.Errorf("Run() = %v, %v, want a single null", items, err)
+	}
+	if items := MustCompileJSONPath("$").Select(nil_data); items != nil {
+		t.Errorf("Select() = %v, want nil", items)
+	}
+}
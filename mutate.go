@@ -0,0 +1,255 @@
+package go_data_chain
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SetMapItem sets a map item, a nil value is replaced with a new map
+// - key: the key to set
+// - value: the value to set, a *Data is unwrapped to its value
+func (m *Data) SetMapItem(key string, value interface{}) error {
+	if m == nil {
+		m = m.missingItem()
+	}
+	if m.value == nil {
+		return m.setValue(map[string]interface{}{key: unwrapValue(value)})
+	}
+	if m.valueKind() != "map" {
+		return m.typeMismatch("map")
+	}
+	if !setMapEntry(m.value, key, unwrapValue(value)) {
+		return fmt.Errorf("can not set key `%s` to `%T` in a `%T`", key, unwrapValue(value), m.value)
+	}
+	return nil
+}
+
+// SetArrayItem replaces an item in the array
+// - index: the index of the item to replace
+// - value: the value to set, a *Data is unwrapped to its value
+func (m *Data) SetArrayItem(index int, value interface{}) error {
+	if m == nil {
+		m = m.missingItem()
+	}
+	items, ok := asArray(m.value)
+	if !ok {
+		return m.typeMismatch("array")
+	}
+	if index < 0 || index >= len(items) {
+		return m.indexOutOfRange(index, len(items))
+	}
+	if !setArrayEntry(m.value, index, unwrapValue(value)) {
+		return fmt.Errorf("can not set index `%v` to `%T` in a `%T`", index, unwrapValue(value), m.value)
+	}
+	return nil
+}
+
+// Append adds items to the end of the array, a nil value is replaced with a new array
+// - values: the values to add, a *Data is unwrapped to its value
+func (m *Data) Append(values ...interface{}) error {
+	if m == nil {
+		m = m.missingItem()
+	}
+	items, _ := asArray(m.value)
+	return m.insert(len(items), values)
+}
+
+// Insert adds an item to the array before the index
+// - index: the position of the new item, the length of the array appends it
+// - value: the value to insert, a *Data is unwrapped to its value
+func (m *Data) Insert(index int, value interface{}) error {
+	return m.insert(index, []interface{}{value})
+}
+
+// insert adds items to the array before the index, a nil value is replaced with a new array
+// typed slices such as []string are replaced with a longer copy of the same type
+func (m *Data) insert(index int, values []interface{}) error {
+	if m == nil {
+		m = m.missingItem()
+	}
+	container := m.value
+	if container == nil {
+		container = []interface{}{}
+	}
+	items, ok := asArray(container)
+	if !ok {
+		return m.typeMismatch("array")
+	}
+	if index < 0 || index > len(items) {
+		return m.indexOutOfRange(index, len(items))
+	}
+	unwrapped := make([]interface{}, len(values))
+	for i, o := range values {
+		unwrapped[i] = unwrapValue(o)
+	}
+	out, err := insertArrayEntries(container, index, unwrapped)
+	if err != nil {
+		return err
+	}
+	return m.setValue(out)
+}
+
+// DeleteMapItem removes an item from the map
+// - key: the key to remove
+func (m *Data) DeleteMapItem(key string) error {
+	if m == nil {
+		m = m.missingItem()
+	}
+	if m.valueKind() != "map" {
+		return m.typeMismatch("map")
+	}
+	if !deleteMapEntry(m.value, key) {
+		return m.keyNotFound(key)
+	}
+	return nil
+}
+
+// DeleteArrayItem removes an item from the array
+// - index: the index of the item to remove
+func (m *Data) DeleteArrayItem(index int) error {
+	if m == nil {
+		m = m.missingItem()
+	}
+	items, ok := asArray(m.value)
+	if !ok {
+		return m.typeMismatch("array")
+	}
+	if index < 0 || index >= len(items) {
+		return m.indexOutOfRange(index, len(items))
+	}
+	out, err := removeArrayEntry(m.value, index)
+	if err != nil {
+		return err
+	}
+	return m.setValue(out)
+}
+
+// Set sets the item at the path expression creating any missing maps and arrays
+// - path: the path expression, see CompilePath for the grammar
+// - value: the value to set, a *Data is unwrapped to its value
+func (m *Data) Set(path string, value interface{}) error {
+	p, err := CompilePath(path)
+	if err != nil {
+		return err
+	}
+	return m.SetPath(p, value)
+}
+
+// SetPath sets the item at a compiled path creating any missing maps and arrays
+// an index past the end of an array grows the array padding it with nil
+// - p: the compiled path
+// - value: the value to set, a *Data is unwrapped to its value
+func (m *Data) SetPath(p *Path, value interface{}) error {
+	if m == nil {
+		m = m.missingItem()
+	}
+	v, err := setIn(m.value, p.segments, unwrapValue(value))
+	if err != nil {
+		return fmt.Errorf("can not set `%s`: %v", p.expr, err)
+	}
+	return m.setValue(v)
+}
+
+// setIn sets the value below a container
+// returns the container, which is new if it was nil or an array had to grow
+func setIn(container interface{}, segments []pathSegment, value interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+	seg := segments[0]
+	if seg.isIndex {
+		items, ok := nativeArray(container)
+		if view, is_array := asArray(container); !ok && is_array && seg.index < len(view) {
+			//other go slices can be changed in place but not grown
+			o, err := setIn(view[seg.index], segments[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			if !setArrayEntry(container, seg.index, o) {
+				return nil, fmt.Errorf("can not set index `%v` in a `%T`", seg.index, container)
+			}
+			return container, nil
+		}
+		if !ok && container != nil {
+			return nil, fmt.Errorf("not an array at index `%v`", seg.index)
+		}
+		for len(items) <= seg.index {
+			items = append(items, nil)
+		}
+		o, err := setIn(items[seg.index], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		items[seg.index] = o
+		return items, nil
+	}
+	items, ok := nativeMap(container)
+	if view, is_map := asMap(container); !ok && is_map && reflect.ValueOf(container).Kind() == reflect.Map {
+		//other go maps such as map[interface{}]interface{} are changed in place
+		o, err := setIn(view[seg.key], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		if !setMapEntry(container, seg.key, o) {
+			return nil, fmt.Errorf("can not set key `%s` in a `%T`", seg.key, container)
+		}
+		return container, nil
+	}
+	if !ok {
+		if container != nil {
+			return nil, fmt.Errorf("not a map at key `%s`", seg.key)
+		}
+		items = map[string]interface{}{}
+	}
+	o, err := setIn(items[seg.key], segments[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	items[seg.key] = o
+	return items, nil
+}
+
+// setValue replaces the value and writes it back into the map or array it was read from
+// an item that does not exist is added to the map it was read from
+// - value: the new value
+func (m *Data) setValue(value interface{}) error {
+	if m.missing && (m.up == nil || m.at == nil) {
+		return fmt.Errorf("can not set an item that does not exist and has no parent")
+	}
+	if m.up != nil && m.at != nil {
+		if m.at.isIndex {
+			if !setArrayEntry(m.up.value, m.at.index, value) {
+				return fmt.Errorf("can not write index `%v` back into the parent array", m.at.index)
+			}
+		} else {
+			if !setMapEntry(m.up.value, m.at.key, value) {
+				return fmt.Errorf("can not write key `%s` back into the parent map", m.at.key)
+			}
+		}
+	}
+	m.value = value
+	m.missing = false
+	return nil
+}
+
+// valueKind returns the kind of the value, nil values return invalid
+func (m *Data) valueKind() string {
+	if m.value == nil {
+		return "invalid"
+	}
+	return m.GetType()
+}
+
+// unwrapValue returns the value held by a Data object or the value itself
+func unwrapValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case *Data:
+		if val == nil {
+			return nil
+		}
+		return val.value
+	case Data:
+		return val.value
+	}
+	return value
+}
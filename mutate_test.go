@@ -0,0 +1,203 @@
+package go_data_chain
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetMapItem(t *testing.T) {
+	var test_data interface{}
+
+	file, _ := ioutil.ReadFile("examples/example_data.yaml")
+	err := yaml.Unmarshal([]byte(file), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	chain_item := chain.GetMapItem("data").GetMapItem("maps")
+	if err := chain_item.SetMapItem("map_string_4", "map_string_4"); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain_item.SetMapItem("map_string_1", chain.Get("data.arrays[0]")); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain_item.DeleteMapItem("map_string_2"); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"map_string_1": "array_string_1",
+		"map_string_3": "map_string_3",
+		"map_string_4": "map_string_4",
+	}
+	if got := test_data.(map[string]interface{})["data"].(map[string]interface{})["maps"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("SetMapItem() = %v, want %v", got, want)
+	}
+	if err := chain_item.DeleteMapItem("map_string_2"); err == nil {
+		t.Errorf("DeleteMapItem() expected an error for a missing key")
+	}
+	if err := chain.Get("data.arrays").SetMapItem("key", 1); err == nil {
+		t.Errorf("SetMapItem() expected an error for an array")
+	}
+}
+
+func TestSetMissingItem(t *testing.T) {
+	value := map[string]interface{}{
+		"data": map[string]interface{}{"items": []interface{}{1}, "name": "web"},
+	}
+	chain := CreateDataChain(value, false)
+	item := chain.Get("data.other")
+	if err := item.SetMapItem("a", 1); err != nil {
+		t.Fatalf("SetMapItem() error = %v", err)
+	}
+	if !item.Exists() || chain.Get("data.other.a").ToInt() != 1 {
+		t.Errorf("SetMapItem() on a missing key should add it to the parent map, got %v", value)
+	}
+	list := chain.Get("data.list")
+	if err := list.Append(1, 2); err != nil || !list.Exists() || chain.Get("data.list").GetArrayCount() != 2 {
+		t.Errorf("Append() on a missing key should add it to the parent map, err = %v", err)
+	}
+	tests := []struct {
+		name string
+		item *Data
+	}{
+		{name: "index", item: chain.Get("data.items[3]")},
+		{name: "below_missing", item: chain.Get("data.none.deeper")},
+		{name: "not_a_map", item: chain.Get("data.name.deeper")},
+		{name: "no_parent", item: chain.Jq(".data | error")},
+		{name: "invalid_path", item: chain.Get("data[")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.item.SetMapItem("a", 1); err == nil {
+				t.Errorf("SetMapItem() expected an error")
+			}
+			if err := tt.item.Append(1); err == nil {
+				t.Errorf("Append() expected an error")
+			}
+			if err := tt.item.Set("a", 1); err == nil {
+				t.Errorf("Set() expected an error")
+			}
+			if tt.item.Exists() {
+				t.Errorf("a failed write should leave the item missing")
+			}
+		})
+	}
+}
+
+func TestArrayMutation(t *testing.T) {
+	var test_data interface{}
+
+	file, _ := ioutil.ReadFile("examples/example_data.yaml")
+	err := yaml.Unmarshal([]byte(file), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	chain_item := chain.GetMapItem("data").GetMapItem("arrays")
+	if err := chain_item.Append("array_string_4", "array_string_5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain_item.Insert(0, "array_string_0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain_item.SetArrayItem(1, "replaced"); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain_item.DeleteArrayItem(2); err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{"array_string_0", "replaced", "array_string_3", "array_string_4", "array_string_5"}
+	//the grown array must be written back into the parent map
+	if got := chain.Get("data.arrays").ToInterface(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Append() = %v, want %v", got, want)
+	}
+	if got := chain_item.GetArrayCount(); got != 5 {
+		t.Errorf("GetArrayCount() = %v, want 5", got)
+	}
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{name: "set_out_of_range", fn: func() error { return chain_item.SetArrayItem(5, "x") }},
+		{name: "insert_out_of_range", fn: func() error { return chain_item.Insert(7, "x") }},
+		{name: "delete_out_of_range", fn: func() error { return chain_item.DeleteArrayItem(-1) }},
+		{name: "append_to_map", fn: func() error { return chain.GetMapItem("data").Append("x") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); err == nil {
+				t.Errorf("%s expected an error", tt.name)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	test_data := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "first"},
+		},
+		"text": "value",
+	}
+	chain := CreateDataChain(test_data, false)
+	tests := []struct {
+		name  string
+		path  string
+		value interface{}
+	}{
+		{name: "existing", path: "items[0].name", value: "renamed"},
+		{name: "new_map", path: "config.server.port", value: 8080},
+		{name: "grow_array", path: "items[2].name", value: "third"},
+		{name: "new_array", path: "list[1]", value: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := chain.Set(tt.path, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			if got := chain.Get(tt.path).ToInterface(); !reflect.DeepEqual(got, tt.value) {
+				t.Errorf("Set() = %v, want %v", got, tt.value)
+			}
+		})
+	}
+	if got := chain.Get("items").GetArrayCount(); got != 3 {
+		t.Errorf("GetArrayCount() = %v, want 3", got)
+	}
+	if got := chain.Get("list").ToInterface(); !reflect.DeepEqual(got, []interface{}{nil, true}) {
+		t.Errorf("Set() = %v, want [<nil> true]", got)
+	}
+	if err := chain.Set("text.child", 1); err == nil {
+		t.Errorf("Set() expected an error when a string is in the way")
+	}
+}
+
+func TestTypedSliceMutations(t *testing.T) {
+	data := map[string]interface{}{
+		"tags":  []string{"a", "c"},
+		"fixed": [2]int{1, 2},
+	}
+	chain := CreateDataChain(data, false)
+	tags := chain.GetMapItem("tags")
+	if err := tags.Insert(1, "b"); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if err := tags.Append("d", "e"); err != nil {
+		t.Fatalf("Append() = %v", err)
+	}
+	if err := tags.DeleteArrayItem(4); err != nil {
+		t.Fatalf("DeleteArrayItem() = %v", err)
+	}
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(data["tags"], want) {
+		t.Errorf("tags = %v, want %v", data["tags"], want)
+	}
+	if err := tags.Append(1); err == nil || !strings.Contains(err.Error(), "can not add `int` to a `[]string`") {
+		t.Errorf("Append() = %v, want an error for an int", err)
+	}
+	if err := chain.GetMapItem("fixed").Append(3); err == nil || !strings.Contains(err.Error(), "can not grow a `[2]int`") {
+		t.Errorf("Append() = %v, want an error for a go array", err)
+	}
+}
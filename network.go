@@ -0,0 +1,128 @@
+package go_data_chain
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// ToIP returns the data as an IP address, a value that can not be converted returns the zero netip.Addr
+func (m *Data) ToIP() netip.Addr {
+	v, err := m.ToIPE()
+	m.addError(err)
+	return v
+}
+
+// ToIPE returns the data as an IP address
+// strings are IPv4 or IPv6 addresses, byte slices are the address as text or 4 or 16 raw bytes
+func (m *Data) ToIPE() (netip.Addr, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	switch val := m.value.(type) {
+	case netip.Addr:
+		return val, nil
+	case net.IP:
+		if ip, ok := netip.AddrFromSlice(val); ok {
+			return ip.Unmap(), nil
+		}
+	case []byte:
+		//a 16 character IPv6 address is also 16 bytes long so the text is tried first
+		if ip, err := netip.ParseAddr(strings.TrimSpace(string(val))); err == nil {
+			return ip, nil
+		}
+		if ip, ok := netip.AddrFromSlice(val); ok {
+			return ip.Unmap(), nil
+		}
+		return m.parseIP(string(val))
+	case string:
+		return m.parseIP(val)
+	}
+	return netip.Addr{}, m.conversionError("netip.Addr", fmt.Errorf("unsupported type"))
+}
+
+// parseIP parses an IP address from text
+func (m *Data) parseIP(s string) (netip.Addr, error) {
+	ip, err := netip.ParseAddr(strings.TrimSpace(s))
+	if err != nil {
+		return netip.Addr{}, m.conversionError("netip.Addr", err)
+	}
+	return ip, nil
+}
+
+// ToIPPrefix returns the data as a CIDR range, a value that can not be converted returns the zero netip.Prefix
+func (m *Data) ToIPPrefix() netip.Prefix {
+	v, err := m.ToIPPrefixE()
+	m.addError(err)
+	return v
+}
+
+// ToIPPrefixE returns the data as a CIDR range such as 10.0.0.0/8 or 2001:db8::/32
+func (m *Data) ToIPPrefixE() (netip.Prefix, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	var s string
+	switch val := m.value.(type) {
+	case netip.Prefix:
+		return val, nil
+	case *net.IPNet:
+		if val != nil {
+			s = val.String()
+		}
+	case net.IPNet:
+		s = val.String()
+	case []byte:
+		s = string(val)
+	case string:
+		s = val
+	default:
+		return netip.Prefix{}, m.conversionError("netip.Prefix", fmt.Errorf("unsupported type"))
+	}
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(s))
+	if err != nil {
+		return netip.Prefix{}, m.conversionError("netip.Prefix", err)
+	}
+	return prefix, nil
+}
+
+// ToURL returns the data as a URL, a value that can not be converted returns nil
+func (m *Data) ToURL() *url.URL {
+	v, err := m.ToURLE()
+	m.addError(err)
+	return v
+}
+
+// ToURLE returns the data as an absolute URL, values without a scheme return an error
+func (m *Data) ToURLE() (*url.URL, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	var s string
+	switch val := m.value.(type) {
+	case *url.URL:
+		if val != nil {
+			u := *val
+			return &u, nil
+		}
+		return nil, m.conversionError("*url.URL", fmt.Errorf("no value"))
+	case url.URL:
+		return &val, nil
+	case []byte:
+		s = string(val)
+	case string:
+		s = val
+	default:
+		return nil, m.conversionError("*url.URL", fmt.Errorf("unsupported type"))
+	}
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return nil, m.conversionError("*url.URL", err)
+	}
+	if u.Scheme == "" {
+		return nil, m.conversionError("*url.URL", fmt.Errorf("missing scheme"))
+	}
+	return u, nil
+}
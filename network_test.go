@@ -0,0 +1,66 @@
+package go_data_chain
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestToIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    netip.Addr
+		wantErr bool
+	}{
+		{name: "ipv4", value: "192.168.1.10", want: netip.MustParseAddr("192.168.1.10")},
+		{name: "ipv6", value: "2001:db8::1", want: netip.MustParseAddr("2001:db8::1")},
+		{name: "raw_bytes", value: []byte{10, 0, 0, 1}, want: netip.MustParseAddr("10.0.0.1")},
+		{name: "text_bytes", value: []byte("10.0.0.1"), want: netip.MustParseAddr("10.0.0.1")},
+		{name: "ipv6_text_bytes", value: []byte("2001:db8::dead:1"), want: netip.MustParseAddr("2001:db8::dead:1")},
+		{name: "mapped_raw_bytes", value: []byte(net.ParseIP("10.0.0.1")), want: netip.MustParseAddr("10.0.0.1")},
+		{name: "invalid_bytes", value: []byte{1, 2, 3}, wantErr: true},
+		{name: "net_ip", value: net.ParseIP("10.0.0.1"), want: netip.MustParseAddr("10.0.0.1")},
+		{name: "invalid", value: "300.1.1.1", wantErr: true},
+		{name: "number", value: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateDataChain(tt.value, false).ToIPE()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToIPE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToIPE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToIPPrefixAndURL(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{
+		"subnet":   "10.0.0.0/8",
+		"bad_net":  "10.0.0.0/33",
+		"api":      "https://api.example.com:8443/v1?debug=true",
+		"relative": "/v1/users",
+	}, true)
+	if got := chain.GetMapItem("subnet").ToIPPrefix(); got.Bits() != 8 || !got.Contains(netip.MustParseAddr("10.1.2.3")) {
+		t.Errorf("ToIPPrefix() = %v, want 10.0.0.0/8", got)
+	}
+	if got := chain.GetMapItem("api").ToURL(); got == nil || got.Hostname() != "api.example.com" || got.Port() != "8443" || got.Query().Get("debug") != "true" {
+		t.Errorf("ToURL() = %v, want the api url", got)
+	}
+	if chain.Err != nil {
+		t.Fatalf("Err = %v, want nil", chain.Err)
+	}
+	if got := chain.GetMapItem("bad_net").ToIPPrefix(); got.IsValid() {
+		t.Errorf("ToIPPrefix() = %v, want the zero prefix", got)
+	}
+	if got := chain.GetMapItem("relative").ToURL(); got != nil {
+		t.Errorf("ToURL() = %v, want nil", got)
+	}
+	if chain.Err == nil || !strings.Contains(chain.Err.Error(), "/bad_net") || !strings.Contains(chain.Err.Error(), "missing scheme") {
+		t.Errorf("Err = %v, want errors for /bad_net and /relative", chain.Err)
+	}
+}
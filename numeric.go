@@ -0,0 +1,205 @@
+package go_data_chain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// NumericMode selects how number conversions handle values that do not fit the target type
+type NumericMode int
+
+const (
+	// NumericWrap uses the go conversion rules, 300 becomes 44 as an int8 and 1.56 becomes 1,
+	// strings that do not fit the target type return an error
+	NumericWrap NumericMode = iota
+	// NumericStrict returns an error for overflow, NaN or Inf and floats that lose their fraction
+	NumericStrict
+	// NumericSaturate clamps values to the smallest or largest value of the target type
+	NumericSaturate
+)
+
+var (
+	// ErrOverflow is returned in strict mode when a value does not fit the target type
+	ErrOverflow = errors.New("value out of range")
+	// ErrNotFinite is returned when NaN or Inf is converted to an integer outside wrap mode
+	ErrNotFinite = errors.New("value is not a finite number")
+	// ErrPrecision is returned in strict mode when a conversion would lose part of the value
+	ErrPrecision = errors.New("value loses precision")
+)
+
+// WithNumericMode returns a copy of the data that converts numbers using the mode
+// the lenient To methods return 0 when a strict conversion fails and add the error to Err in safe mode
+// - mode: the numeric mode
+func (m *Data) WithNumericMode(mode NumericMode) *Data {
+	return m.withOptions(func(o *options) {
+		o.numeric = mode
+	})
+}
+
+// NumericMode returns the numeric mode used by the data
+func (m *Data) NumericMode() NumericMode {
+	if m == nil {
+		return NumericWrap
+	}
+	return m.opts.numeric
+}
+
+// recordError adds a conversion error from a lenient To method to the root of a safe chain
+// in wrap mode the lenient methods ignore errors the way they always have
+// - err: the error, nil is ignored
+func (m *Data) recordError(err error) {
+	if m.Exists() && err != nil && m.opts.numeric != NumericWrap {
+		m.addError(err)
+	}
+}
+
+// fitInt fits an integer into the signed integer size using the numeric mode
+// - to: the name of the type for errors
+// - i: the value
+// - bits: the size of the target type
+func (m *Data) fitInt(to string, i int64, bits int) (int64, error) {
+	lo, hi := int64(-1)<<(bits-1), int64(1)<<(bits-1)-1
+	if i >= lo && i <= hi {
+		return i, nil
+	}
+	switch m.opts.numeric {
+	case NumericStrict:
+		return 0, m.conversionError(to, ErrOverflow)
+	case NumericSaturate:
+		if i < lo {
+			return lo, nil
+		}
+		return hi, nil
+	}
+	//keep the low bits the way a go conversion does
+	return i << (64 - bits) >> (64 - bits), nil
+}
+
+// fitUint fits an unsigned integer into the signed integer size using the numeric mode
+func (m *Data) fitUint(to string, u uint64, bits int) (int64, error) {
+	if u <= math.MaxInt64 {
+		return m.fitInt(to, int64(u), bits)
+	}
+	switch m.opts.numeric {
+	case NumericStrict:
+		return 0, m.conversionError(to, ErrOverflow)
+	case NumericSaturate:
+		return int64(1)<<(bits-1) - 1, nil
+	}
+	return m.fitInt(to, int64(u), bits)
+}
+
+// fitFloat converts a float to the signed integer size using the numeric mode
+func (m *Data) fitFloat(to string, f float64, bits int) (int64, error) {
+	//the limits are powers of two so they are exact as floats
+	lo, hi := -math.Ldexp(1, bits-1), math.Ldexp(1, bits-1)
+	switch m.opts.numeric {
+	case NumericStrict:
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, m.conversionError(to, ErrNotFinite)
+		}
+		if f < lo || f >= hi {
+			return 0, m.conversionError(to, ErrOverflow)
+		}
+		if f != math.Trunc(f) {
+			return 0, m.conversionError(to, ErrPrecision)
+		}
+	case NumericSaturate:
+		if math.IsNaN(f) {
+			return 0, m.conversionError(to, ErrNotFinite)
+		}
+		if f < lo {
+			return int64(-1) << (bits - 1), nil
+		}
+		if f >= hi {
+			return int64(1)<<(bits-1) - 1, nil
+		}
+	}
+	return m.fitInt(to, int64(f), bits)
+}
+
+// fitUintSize fits an unsigned integer into the unsigned integer size using the numeric mode
+func (m *Data) fitUintSize(to string, u uint64, bits int) (uint64, error) {
+	hi := uint64(math.MaxUint64) >> (64 - bits)
+	if u <= hi {
+		return u, nil
+	}
+	switch m.opts.numeric {
+	case NumericStrict:
+		return 0, m.conversionError(to, ErrOverflow)
+	case NumericSaturate:
+		return hi, nil
+	}
+	return u & hi, nil
+}
+
+// fitNegative fits a negative integer into the unsigned integer size using the numeric mode
+func (m *Data) fitNegative(to string, i int64, bits int) (uint64, error) {
+	switch m.opts.numeric {
+	case NumericStrict:
+		return 0, m.conversionError(to, ErrOverflow)
+	case NumericSaturate:
+		return 0, nil
+	}
+	return m.fitUintSize(to, uint64(i), bits)
+}
+
+// fitFloatUint converts a float to the unsigned integer size using the numeric mode
+func (m *Data) fitFloatUint(to string, f float64, bits int) (uint64, error) {
+	hi := math.Ldexp(1, bits)
+	switch m.opts.numeric {
+	case NumericStrict:
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, m.conversionError(to, ErrNotFinite)
+		}
+		if f < 0 || f >= hi {
+			return 0, m.conversionError(to, ErrOverflow)
+		}
+		if f != math.Trunc(f) {
+			return 0, m.conversionError(to, ErrPrecision)
+		}
+	case NumericSaturate:
+		if math.IsNaN(f) {
+			return 0, m.conversionError(to, ErrNotFinite)
+		}
+		if f < 0 {
+			return 0, nil
+		}
+		if f >= hi {
+			return uint64(math.MaxUint64) >> (64 - bits), nil
+		}
+	}
+	if f < 0 {
+		//negative floats wrap the way they do through a signed integer
+		return m.fitUintSize(to, uint64(int64(f)), bits)
+	}
+	return m.fitUintSize(to, uint64(f), bits)
+}
+
+// fitFloatSize fits a float into a float32 or float64 using the numeric mode
+func (m *Data) fitFloatSize(to string, f float64, bits int) (float64, error) {
+	if bits != 32 || math.IsNaN(f) || math.IsInf(f, 0) || math.Abs(f) <= math.MaxFloat32 {
+		return f, nil
+	}
+	switch m.opts.numeric {
+	case NumericStrict:
+		return 0, m.conversionError(to, ErrOverflow)
+	case NumericSaturate:
+		return math.Copysign(math.MaxFloat32, f), nil
+	}
+	return f, nil
+}
+
+// String returns the numeric mode as a string
+func (mode NumericMode) String() string {
+	switch mode {
+	case NumericWrap:
+		return "wrap"
+	case NumericStrict:
+		return "strict"
+	case NumericSaturate:
+		return "saturate"
+	}
+	return fmt.Sprintf("NumericMode(%d)", int(mode))
+}
@@ -0,0 +1,107 @@
+package go_data_chain
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNumericMode(t *testing.T) {
+	data := map[string]interface{}{
+		"small":    300,
+		"negative": -300,
+		"huge":     1e20,
+		"fraction": 1.56,
+		"nan":      math.NaN(),
+		"inf":      math.Inf(1),
+		"big_text": "99999999999",
+		"text_300": "300",
+		"text_neg": "-129",
+		"exact":    int64(1) << 53,
+		"inexact":  int64(1)<<53 + 1,
+	}
+	tests := []struct {
+		name    string
+		mode    NumericMode
+		key     string
+		convert func(d *Data) (interface{}, error)
+		want    interface{}
+		wantErr error
+	}{
+		{name: "wrap_int8", mode: NumericWrap, key: "small", convert: func(d *Data) (interface{}, error) { return d.ToInt8E() }, want: int8(44)},
+		{name: "strict_int8", mode: NumericStrict, key: "small", convert: func(d *Data) (interface{}, error) { return d.ToInt8E() }, want: int8(0), wantErr: ErrOverflow},
+		{name: "saturate_int8", mode: NumericSaturate, key: "small", convert: func(d *Data) (interface{}, error) { return d.ToInt8E() }, want: int8(127)},
+		{name: "saturate_int8_negative", mode: NumericSaturate, key: "negative", convert: func(d *Data) (interface{}, error) { return d.ToInt8E() }, want: int8(-128)},
+		{name: "strict_int64_huge", mode: NumericStrict, key: "huge", convert: func(d *Data) (interface{}, error) { return d.ToInt64E() }, want: int64(0), wantErr: ErrOverflow},
+		{name: "saturate_int64_huge", mode: NumericSaturate, key: "huge", convert: func(d *Data) (interface{}, error) { return d.ToInt64E() }, want: int64(math.MaxInt64)},
+		{name: "wrap_fraction", mode: NumericWrap, key: "fraction", convert: func(d *Data) (interface{}, error) { return d.ToIntE() }, want: 1},
+		{name: "strict_fraction", mode: NumericStrict, key: "fraction", convert: func(d *Data) (interface{}, error) { return d.ToIntE() }, want: 0, wantErr: ErrPrecision},
+		{name: "saturate_fraction", mode: NumericSaturate, key: "fraction", convert: func(d *Data) (interface{}, error) { return d.ToIntE() }, want: 1},
+		{name: "strict_nan", mode: NumericStrict, key: "nan", convert: func(d *Data) (interface{}, error) { return d.ToInt32E() }, want: int32(0), wantErr: ErrNotFinite},
+		{name: "saturate_nan", mode: NumericSaturate, key: "nan", convert: func(d *Data) (interface{}, error) { return d.ToInt32E() }, want: int32(0), wantErr: ErrNotFinite},
+		{name: "saturate_inf", mode: NumericSaturate, key: "inf", convert: func(d *Data) (interface{}, error) { return d.ToInt32E() }, want: int32(math.MaxInt32)},
+		{name: "wrap_string_int8", mode: NumericWrap, key: "text_300", convert: func(d *Data) (interface{}, error) { return d.ToInt8E() }, want: int8(0), wantErr: strconv.ErrRange},
+		{name: "wrap_string_int8_negative", mode: NumericWrap, key: "text_neg", convert: func(d *Data) (interface{}, error) { return d.ToInt8E() }, want: int8(0), wantErr: strconv.ErrRange},
+		{name: "wrap_string_uint8_negative", mode: NumericWrap, key: "text_neg", convert: func(d *Data) (interface{}, error) { return d.ToUint8E() }, want: uint8(0), wantErr: strconv.ErrSyntax},
+		{name: "strict_string_int8", mode: NumericStrict, key: "text_300", convert: func(d *Data) (interface{}, error) { return d.ToInt8E() }, want: int8(0), wantErr: ErrOverflow},
+		{name: "saturate_string_uint8", mode: NumericSaturate, key: "text_neg", convert: func(d *Data) (interface{}, error) { return d.ToUint8E() }, want: uint8(0)},
+		{name: "saturate_string", mode: NumericSaturate, key: "big_text", convert: func(d *Data) (interface{}, error) { return d.ToInt32E() }, want: int32(math.MaxInt32)},
+		{name: "strict_float32", mode: NumericStrict, key: "huge", convert: func(d *Data) (interface{}, error) { return d.ToFloat32E() }, want: float32(1e20)},
+		{name: "strict_float64_exact", mode: NumericStrict, key: "exact", convert: func(d *Data) (interface{}, error) { return d.ToFloat64E() }, want: float64(1 << 53)},
+		{name: "strict_float64_inexact", mode: NumericStrict, key: "inexact", convert: func(d *Data) (interface{}, error) { return d.ToFloat64E() }, want: 0.0, wantErr: ErrPrecision},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := CreateDataChain(data, false).WithNumericMode(tt.mode)
+			got, err := tt.convert(chain.GetMapItem(tt.key))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("convert() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumericModeLenient(t *testing.T) {
+	data := map[string]interface{}{"small": 300, "huge": 1e300, "maybe": "maybe"}
+	//the default keeps the go conversion rules
+	if got := CreateDataChain(data, false).GetMapItem("small").ToInt8(); got != 44 {
+		t.Errorf("ToInt8() = %v, want 44", got)
+	}
+	//strings that do not fit are not wrapped
+	if got := CreateDataChain("3000000000", false).ToInt32(); got != 0 {
+		t.Errorf("ToInt32() = %v, want 0", got)
+	}
+	chain := CreateDataChain(data, true)
+	chain.GetMapItem("other")
+	strict := chain.WithNumericMode(NumericStrict)
+	if strict.Err != nil {
+		t.Errorf("Err of the copy = %v, want nil", strict.Err)
+	}
+	chain.Reset()
+	if got := strict.GetMapItem("small").ToInt8(); got != 0 {
+		t.Errorf("ToInt8() = %v, want 0", got)
+	}
+	if got := strict.GetMapItem("huge").ToFloat32(); got != 0 {
+		t.Errorf("ToFloat32() = %v, want 0", got)
+	}
+	//the errors of the copy go to the chain it was made from
+	if strict.Err != nil || chain.Err == nil || !strings.Contains(chain.Err.Error(), ErrOverflow.Error()) {
+		t.Fatalf("Err = %v, want the overflow errors on the chain", chain.Err)
+	}
+	if got := strict.GetMapItem("maybe").ToBool(); got || !strings.Contains(chain.Err.Error(), "/maybe") {
+		t.Errorf("ToBool() = %v, Err = %v, want false and an error for /maybe", got, chain.Err)
+	}
+	//the mode can be changed for a single call
+	if got := strict.GetMapItem("small").WithNumericMode(NumericSaturate).ToInt8(); got != 127 {
+		t.Errorf("ToInt8() = %v, want 127", got)
+	}
+	if got := strict.GetMapItem("small").NumericMode(); got != NumericStrict {
+		t.Errorf("NumericMode() = %v, want strict", got)
+	}
+}
@@ -0,0 +1,307 @@
+package go_data_chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PatchOperation is a single RFC 6902 JSON Patch operation
+type PatchOperation struct {
+	Op    string
+	Path  string
+	From  string
+	Value interface{}
+}
+
+// PatchError is returned when an operation of a patch fails
+type PatchError struct {
+	Index int
+	Op    string
+	Path  string
+	Err   error
+}
+
+// Error returns the error as a string
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("patch operation %d (%s `%s`) failed: %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+// Unwrap returns the reason the operation failed
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON encodes the operation only including the members it uses
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{"op": op.Op, "path": op.Path}
+	switch op.Op {
+	case "add", "replace", "test":
+		out["value"] = op.Value
+	case "move", "copy":
+		out["from"] = op.From
+	}
+	return json.Marshal(out)
+}
+
+// ParsePatch decodes an RFC 6902 JSON Patch document
+// - data: the json array of operations
+func ParsePatch(data []byte) ([]PatchOperation, error) {
+	var raw []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid patch: %v", err)
+	}
+	ops := make([]PatchOperation, 0, len(raw))
+	for i, member := range raw {
+		var op PatchOperation
+		for name, target := range map[string]*string{"op": &op.Op, "path": &op.Path, "from": &op.From} {
+			if v, ok := member[name]; ok {
+				if err := json.Unmarshal(v, target); err != nil {
+					return nil, fmt.Errorf("invalid patch operation %d: `%s` must be a string", i, name)
+				}
+			}
+		}
+		if _, ok := member["path"]; !ok {
+			return nil, fmt.Errorf("invalid patch operation %d: missing `path`", i)
+		}
+		switch op.Op {
+		case "add", "replace", "test":
+			v, ok := member["value"]
+			if !ok {
+				return nil, fmt.Errorf("invalid patch operation %d: missing `value`", i)
+			}
+			if err := json.Unmarshal(v, &op.Value); err != nil {
+				return nil, fmt.Errorf("invalid patch operation %d: %v", i, err)
+			}
+		case "move", "copy":
+			if _, ok := member["from"]; !ok {
+				return nil, fmt.Errorf("invalid patch operation %d: missing `from`", i)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("invalid patch operation %d: unknown op `%s`", i, op.Op)
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// ApplyPatch applies RFC 6902 JSON Patch operations to the data
+// only the maps and arrays on the path of an operation are copied and changed so if any of them fail nothing changes,
+// maps and arrays keep their go types and an operation fails if a new item does not fit them,
+// a root map is patched in place and any other new root replaces the value of the data
+// - ops: the operations to apply
+// returns a *PatchError for the first operation that failed
+func (m *Data) ApplyPatch(ops []PatchOperation) error {
+	if m == nil {
+		m = m.missingItem()
+	}
+	doc := m.value
+	for i, op := range ops {
+		var err error
+		if doc, err = applyOperation(doc, op); err != nil {
+			return &PatchError{Index: i, Op: op.Op, Path: op.Path, Err: err}
+		}
+	}
+	//keep the original map so references to it see the changes
+	if replaceMapEntries(m.value, doc) {
+		return nil
+	}
+	return m.setValue(doc)
+}
+
+// ApplyPatchJSON decodes an RFC 6902 JSON Patch document and applies it to the data
+// - data: the json array of operations
+func (m *Data) ApplyPatchJSON(data []byte) error {
+	ops, err := ParsePatch(data)
+	if err != nil {
+		return err
+	}
+	return m.ApplyPatch(ops)
+}
+
+// applyOperation applies a single operation to a document
+// returns the document, which is new if the root was replaced
+func applyOperation(doc interface{}, op PatchOperation) (interface{}, error) {
+	path, err := ParsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add":
+		return patchAdd(doc, path, deepCopy(unwrapValue(op.Value)))
+	case "remove":
+		doc, _, err = patchRemove(doc, path)
+		return doc, err
+	case "replace":
+		if _, err := patchGet(doc, path); err != nil {
+			return nil, err
+		}
+		if doc, _, err = patchRemove(doc, path); err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, deepCopy(unwrapValue(op.Value)))
+	case "test":
+		value, err := patchGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !deepEqual(value, unwrapValue(op.Value)) {
+			return nil, fmt.Errorf("test failed: value is `%v`", value)
+		}
+		return doc, nil
+	case "move", "copy":
+		from, err := ParsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := patchGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return patchAdd(doc, path, deepCopy(value))
+		}
+		if op.From == op.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("can not move `%s` into one of its children", op.From)
+		}
+		if doc, _, err = patchRemove(doc, from); err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, value)
+	}
+	return nil, fmt.Errorf("unknown op `%s`", op.Op)
+}
+
+// patchGet returns the value at the pointer tokens
+func patchGet(doc interface{}, tokens []string) (interface{}, error) {
+	for _, token := range tokens {
+		if items, ok := asMap(doc); ok {
+			o, ok := items[token]
+			if !ok {
+				return nil, fmt.Errorf("key `%s` does not exist", token)
+			}
+			doc = o
+		} else if items, ok := asArray(doc); ok {
+			index, err := parsePointerIndex(token, len(items))
+			if err != nil {
+				return nil, err
+			}
+			if index >= len(items) {
+				return nil, fmt.Errorf("index out of range: `%s`", token)
+			}
+			doc = items[index]
+		} else {
+			return nil, fmt.Errorf("map with key `%s` does not exist", token)
+		}
+	}
+	return doc, nil
+}
+
+// patchAt calls fn with the container holding the last token
+// every container on the path is copied before it is changed so the original document is left untouched
+func patchAt(doc interface{}, tokens []string, fn func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+	token := tokens[0]
+	if o, exists, is_map := mapItem(doc, token); is_map {
+		if !exists {
+			return nil, fmt.Errorf("key `%s` does not exist", token)
+		}
+		child, err := patchAt(o, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		return patchSetKey(doc, token, child)
+	}
+	if items, ok := asArray(doc); ok {
+		index, err := parsePointerIndex(token, len(items))
+		if err != nil {
+			return nil, err
+		}
+		if index >= len(items) {
+			return nil, fmt.Errorf("index out of range: `%s`", token)
+		}
+		child, err := patchAt(items[index], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		out := shallowCopy(doc)
+		if !setArrayEntry(out, index, child) {
+			return nil, fmt.Errorf("can not set index `%v` to `%T` in a `%T`", index, child, out)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("map with key `%s` does not exist", token)
+}
+
+// patchSetKey returns a copy of the map with the key set
+func patchSetKey(container interface{}, key string, value interface{}) (interface{}, error) {
+	out := shallowCopy(container)
+	if !setMapEntry(out, key, value) {
+		return nil, fmt.Errorf("can not set key `%s` to `%T` in a `%T`", key, value, out)
+	}
+	return out, nil
+}
+
+// patchAdd adds or replaces the value at the pointer tokens
+func patchAdd(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return patchAt(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		if _, _, is_map := mapItem(container, token); is_map {
+			return patchSetKey(container, token, value)
+		}
+		if items, ok := asArray(container); ok {
+			index, err := parsePointerIndex(token, len(items))
+			if err != nil {
+				return nil, err
+			}
+			if index > len(items) {
+				return nil, fmt.Errorf("index out of range: `%s`", token)
+			}
+			return insertArrayEntries(shallowCopy(container), index, []interface{}{value})
+		}
+		return nil, fmt.Errorf("map with key `%s` does not exist", token)
+	})
+}
+
+// patchRemove removes the value at the pointer tokens
+// returns the document and the removed value
+func patchRemove(doc interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+	var removed interface{}
+	doc, err := patchAt(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		if o, exists, is_map := mapItem(container, token); is_map {
+			if !exists {
+				return nil, fmt.Errorf("key `%s` does not exist", token)
+			}
+			removed = o
+			out := shallowCopy(container)
+			if !deleteMapEntry(out, token) {
+				return nil, fmt.Errorf("can not remove key `%s` from a `%T`", token, out)
+			}
+			return out, nil
+		}
+		if items, ok := asArray(container); ok {
+			index, err := parsePointerIndex(token, len(items))
+			if err != nil {
+				return nil, err
+			}
+			if index >= len(items) {
+				return nil, fmt.Errorf("index out of range: `%s`", token)
+			}
+			removed = items[index]
+			return removeArrayEntry(shallowCopy(container), index)
+		}
+		return nil, fmt.Errorf("map with key `%s` does not exist", token)
+	})
+	return doc, removed, err
+}
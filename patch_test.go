@@ -0,0 +1,191 @@
+package go_data_chain
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{
+			name:  "add",
+			patch: `[{"op": "add", "path": "/data/maps/map_string_4", "value": "map_string_4"}]`,
+			want:  `{"data": {"arrays": ["a", "b"], "maps": {"map_string_1": "x", "map_string_4": "map_string_4"}}}`,
+		},
+		{
+			name:  "add_array_end",
+			patch: `[{"op": "add", "path": "/data/arrays/-", "value": "c"}]`,
+			want:  `{"data": {"arrays": ["a", "b", "c"], "maps": {"map_string_1": "x"}}}`,
+		},
+		{
+			name:  "add_array_insert",
+			patch: `[{"op": "add", "path": "/data/arrays/0", "value": null}]`,
+			want:  `{"data": {"arrays": [null, "a", "b"], "maps": {"map_string_1": "x"}}}`,
+		},
+		{
+			name:  "remove",
+			patch: `[{"op": "remove", "path": "/data/arrays/0"}]`,
+			want:  `{"data": {"arrays": ["b"], "maps": {"map_string_1": "x"}}}`,
+		},
+		{
+			name:  "replace",
+			patch: `[{"op": "replace", "path": "/data/maps", "value": 5}]`,
+			want:  `{"data": {"arrays": ["a", "b"], "maps": 5}}`,
+		},
+		{
+			name:  "move",
+			patch: `[{"op": "move", "from": "/data/maps/map_string_1", "path": "/data/moved"}]`,
+			want:  `{"data": {"arrays": ["a", "b"], "maps": {}, "moved": "x"}}`,
+		},
+		{
+			name:  "copy",
+			patch: `[{"op": "copy", "from": "/data/arrays", "path": "/copied"}]`,
+			want:  `{"copied": ["a", "b"], "data": {"arrays": ["a", "b"], "maps": {"map_string_1": "x"}}}`,
+		},
+		{
+			name:  "test",
+			patch: `[{"op": "test", "path": "/data/arrays/1", "value": "b"}, {"op": "remove", "path": "/data/maps"}]`,
+			want:  `{"data": {"arrays": ["a", "b"]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var test_data, want interface{}
+			if err := json.Unmarshal([]byte(`{"data": {"arrays": ["a", "b"], "maps": {"map_string_1": "x"}}}`), &test_data); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			chain := CreateDataChain(test_data, false)
+			if err := chain.ApplyPatchJSON([]byte(tt.patch)); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(test_data, want) {
+				t.Errorf("ApplyPatch() = %v, want %v", test_data, want)
+			}
+		})
+	}
+}
+
+func TestApplyPatchAtomic(t *testing.T) {
+	var test_data interface{}
+
+	file, _ := ioutil.ReadFile("examples/example_data.yaml")
+	err := yaml.Unmarshal([]byte(file), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	ops := []PatchOperation{
+		{Op: "replace", Path: "/data/maps/map_string_1", Value: "changed"},
+		{Op: "test", Path: "/data/convert_int/int_int", Value: 3.0},
+		{Op: "remove", Path: "/data/arrays/7"},
+	}
+	err = chain.ApplyPatch(ops)
+	var patch_err *PatchError
+	if !errors.As(err, &patch_err) {
+		t.Fatalf("ApplyPatch() error = %v, want a *PatchError", err)
+	}
+	if patch_err.Index != 2 || patch_err.Path != "/data/arrays/7" {
+		t.Errorf("PatchError = %v, want index 2 and path /data/arrays/7", patch_err)
+	}
+	if got := chain.Get("data.maps.map_string_1").ToString(); got != "map_string_1" {
+		t.Errorf("ApplyPatch() changed the data to %v", got)
+	}
+	chain_item := chain.GetMapItem("data").GetMapItem("arrays")
+	if err := chain_item.ApplyPatch([]PatchOperation{{Op: "add", Path: "/-", Value: "array_string_4"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := chain.Pointer("/data/arrays/3").ToString(); got != "array_string_4" {
+		t.Errorf("ApplyPatch() = %v, want array_string_4", got)
+	}
+}
+
+func TestParsePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		wantErr bool
+	}{
+		{name: "valid", args: `[{"op": "add", "path": "/a", "value": null}]`, wantErr: false},
+		{name: "not_json", args: `[{`, wantErr: true},
+		{name: "unknown_op", args: `[{"op": "merge", "path": "/a"}]`, wantErr: true},
+		{name: "missing_value", args: `[{"op": "add", "path": "/a"}]`, wantErr: true},
+		{name: "missing_from", args: `[{"op": "move", "path": "/a"}]`, wantErr: true},
+		{name: "missing_path", args: `[{"op": "remove"}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePatch([]byte(tt.args)); (err != nil) != tt.wantErr {
+				t.Errorf("ParsePatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	b, _ := json.Marshal(PatchOperation{Op: "add", Path: "/a", Value: nil})
+	if got := string(b); got != `{"op":"add","path":"/a","value":null}` {
+		t.Errorf("MarshalJSON() = %v", got)
+	}
+}
+
+func TestApplyPatchOtherShapes(t *testing.T) {
+	data := map[interface{}]interface{}{"name": "web", "port": 80}
+	chain := CreateDataChain(data, false)
+	ops := []PatchOperation{
+		{Op: "replace", Path: "/port", Value: 8080},
+		{Op: "add", Path: "/tls", Value: true},
+		{Op: "remove", Path: "/name"},
+	}
+	if err := chain.ApplyPatch(ops); err != nil {
+		t.Fatal(err)
+	}
+	if want := (map[interface{}]interface{}{"port": 8080, "tls": true}); !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+	//later writes still reach the caller's map
+	if err := chain.SetMapItem("name", "api"); err != nil || data["name"] != "api" {
+		t.Errorf("SetMapItem() = %v, data = %v, want the name in the caller's map", err, data)
+	}
+
+	type item struct{ Name string }
+	root := map[string]interface{}{"items": []item{{Name: "a"}}, "ports": []int{80}, "nested": map[string]interface{}{"list": []string{"x"}}}
+	nested := root["nested"].(map[string]interface{})
+	chain = CreateDataChain(root, false)
+	if err := chain.ApplyPatch([]PatchOperation{{Op: "add", Path: "/new", Value: 1}, {Op: "add", Path: "/ports/-", Value: 443}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := root["items"].([]item); !ok {
+		t.Errorf("items = %T, want the untouched []item", root["items"])
+	}
+	if got, ok := root["ports"].([]int); !ok || !reflect.DeepEqual(got, []int{80, 443}) {
+		t.Errorf("ports = %#v, want []int{80, 443}", root["ports"])
+	}
+	if root["new"] != 1 || reflect.ValueOf(root["nested"]).Pointer() != reflect.ValueOf(nested).Pointer() {
+		t.Errorf("root = %v, want new added and nested left as the same map", root)
+	}
+	if err := chain.ApplyPatch([]PatchOperation{{Op: "add", Path: "/ports/-", Value: "https"}}); err == nil || len(root["ports"].([]int)) != 2 {
+		t.Errorf("ApplyPatch() = %v, want an error for a string in a []int and no change", err)
+	}
+
+	if err := chain.ApplyPatchJSON([]byte(`[{"op": "add", "path": "", "value": [1]}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := chain.ToInterface(); !reflect.DeepEqual(got, []interface{}{1.0}) {
+		t.Errorf("ApplyPatch() root = %v, want [1]", got)
+	}
+
+	typed := map[string]int{"cpu": 2}
+	err := CreateDataChain(typed, false).ApplyPatch([]PatchOperation{{Op: "add", Path: "/memory", Value: "512Mi"}})
+	if err == nil || typed["cpu"] != 2 || len(typed) != 1 {
+		t.Errorf("ApplyPatch() = %v, typed = %v, want an error and no change", err, typed)
+	}
+}
@@ -0,0 +1,300 @@
+package go_data_chain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a single step of a path, either a map key or an array index
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Path is a compiled path expression that can be reused with GetPath
+type Path struct {
+	expr     string
+	segments []pathSegment
+}
+
+// CompilePath parses a path expression so it can be reused
+// - expr: the path expression to parse
+//
+// The grammar of a path expression is:
+//
+//	path    = [ step ] { "." key | "[" index "]" | "[" quoted "]" }
+//	step    = key | "[" index "]" | "[" quoted "]"
+//	key     = one or more characters other than ".", "[" and "]"
+//	index   = one or more decimal digits
+//	quoted  = a string in double or single quotes
+//
+// Inside a key or a quoted string a backslash escapes the next character, so
+// `a\.b` and `["a.b"]` both refer to the single key "a.b". An empty
+// expression refers to the data itself.
+//
+// Examples: `data.maps.map_string_1`, `items[3].name`, `data["key.with.dots"]`
+func CompilePath(expr string) (*Path, error) {
+	p := &Path{expr: expr}
+	i := 0
+	first := true
+	for i < len(expr) {
+		switch expr[i] {
+		case '.':
+			if first {
+				return nil, fmt.Errorf("invalid path `%s`: unexpected `.` at %d", expr, i)
+			}
+			i++
+			key, next, err := parsePathKey(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			p.segments = append(p.segments, pathSegment{key: key})
+			i = next
+		case '[':
+			seg, next, err := parsePathBracket(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			p.segments = append(p.segments, seg)
+			i = next
+		default:
+			if !first {
+				return nil, fmt.Errorf("invalid path `%s`: unexpected `%c` at %d", expr, expr[i], i)
+			}
+			key, next, err := parsePathKey(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			p.segments = append(p.segments, pathSegment{key: key})
+			i = next
+		}
+		first = false
+	}
+	return p, nil
+}
+
+// MustCompilePath is like CompilePath but panics if the expression is invalid
+// - expr: the path expression to parse
+func MustCompilePath(expr string) *Path {
+	p, err := CompilePath(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the expression the path was compiled from
+func (p *Path) String() string {
+	return p.expr
+}
+
+// parsePathKey reads an unquoted key starting at pos
+// returns the key and the position after it
+func parsePathKey(expr string, pos int) (string, int, error) {
+	var sb strings.Builder
+	i := pos
+	for i < len(expr) {
+		c := expr[i]
+		if c == '.' || c == '[' {
+			break
+		}
+		if c == ']' {
+			return "", 0, fmt.Errorf("invalid path `%s`: unexpected `]` at %d", expr, i)
+		}
+		if c == '\\' {
+			if i+1 >= len(expr) {
+				return "", 0, fmt.Errorf("invalid path `%s`: dangling escape at %d", expr, i)
+			}
+			i++
+			c = expr[i]
+		}
+		sb.WriteByte(c)
+		i++
+	}
+	if i == pos {
+		return "", 0, fmt.Errorf("invalid path `%s`: empty key at %d", expr, pos)
+	}
+	return sb.String(), i, nil
+}
+
+// parsePathBracket reads a bracketed index or quoted key starting at pos
+// returns the segment and the position after the closing bracket
+func parsePathBracket(expr string, pos int) (pathSegment, int, error) {
+	i := pos + 1
+	if i >= len(expr) {
+		return pathSegment{}, 0, fmt.Errorf("invalid path `%s`: unterminated `[` at %d", expr, pos)
+	}
+	if quote := expr[i]; quote == '"' || quote == '\'' {
+		var sb strings.Builder
+		i++
+		for {
+			if i >= len(expr) {
+				return pathSegment{}, 0, fmt.Errorf("invalid path `%s`: unterminated string at %d", expr, pos+1)
+			}
+			c := expr[i]
+			if c == quote {
+				break
+			}
+			if c == '\\' {
+				if i+1 >= len(expr) {
+					return pathSegment{}, 0, fmt.Errorf("invalid path `%s`: dangling escape at %d", expr, i)
+				}
+				i++
+				c = expr[i]
+			}
+			sb.WriteByte(c)
+			i++
+		}
+		i++
+		if i >= len(expr) || expr[i] != ']' {
+			return pathSegment{}, 0, fmt.Errorf("invalid path `%s`: expected `]` at %d", expr, i)
+		}
+		return pathSegment{key: sb.String()}, i + 1, nil
+	}
+	end := strings.IndexByte(expr[i:], ']')
+	if end < 0 {
+		return pathSegment{}, 0, fmt.Errorf("invalid path `%s`: unterminated `[` at %d", expr, pos)
+	}
+	digits := expr[i : i+end]
+	index, err := strconv.Atoi(digits)
+	if err != nil || digits == "" || digits[0] == '-' || digits[0] == '+' {
+		return pathSegment{}, 0, fmt.Errorf("invalid path `%s`: invalid index `%s` at %d", expr, digits, i)
+	}
+	return pathSegment{index: index, isIndex: true}, i + end + 1, nil
+}
+
+// Get returns the item at the path expression
+// - path: the path expression, see CompilePath for the grammar
+// returns a Data object for the path, if the path does not exist or is invalid the Data object is missing
+func (m *Data) Get(path string) *Data {
+	p, err := CompilePath(path)
+	if err != nil {
+		m.addError(err)
+		return m.missingItem()
+	}
+	return m.GetPath(p)
+}
+
+// GetPath returns the item at a compiled path
+// each step is resolved with GetMapItem or GetArrayItem
+// - p: the compiled path
+// returns a Data object for the path, if the path does not exist the Data object is missing
+func (m *Data) GetPath(p *Path) *Data {
+	if m == nil {
+		return m.missingItem()
+	}
+	current := m
+	for _, seg := range p.segments {
+		if seg.isIndex {
+			current = current.GetArrayItem(seg.index)
+		} else {
+			current = current.GetMapItem(seg.key)
+		}
+	}
+	return current
+}
+
+// addError adds an error to the MultiError of the root of a safe chain
+// - err: the error to add, nil is ignored
+// missing data does not add errors as the error was added when it was read
+func (m *Data) addError(err error) {
+	if m.Exists() && m.errSink != nil && err != nil {
+		m.errSink.Err = appendError(m.errSink.Err, err)
+	}
+}
+
+// lookup resolves a compiled path without adding errors to the chain
+// returns the item and false if a key or index on the path does not exist
+func (m *Data) lookup(p *Path) (*Data, bool) {
+	current := m
+	for _, seg := range p.segments {
+		if seg.isIndex {
+			items, ok := asArray(current.value)
+			if !ok || seg.index >= len(items) {
+				return nil, false
+			}
+			current = current.child(items[seg.index], seg)
+		} else {
+			value, exists, _ := mapItem(current.value, seg.key)
+			if !exists {
+				return nil, false
+			}
+			current = current.child(value, seg)
+		}
+	}
+	return current, true
+}
+
+//********
+//Location
+//********
+
+// Path returns the path expression of the data e.g. `data.items[0]["key.with.dots"]`
+// relative to the value passed to CreateDataChain, the expression can be passed to Get
+func (m *Data) Path() string {
+	var parts []string
+	for item := m; item != nil && item.at != nil; item = item.up {
+		parts = append(parts, formatPathSegment(*item.at, item.up == nil || item.up.at == nil))
+	}
+	var sb strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		sb.WriteString(parts[i])
+	}
+	return sb.String()
+}
+
+// formatPathSegment formats a single step of a path expression
+// keys that can not be written plainly are quoted
+// - first: true if the step starts the expression so a key has no leading `.`
+func formatPathSegment(seg pathSegment, first bool) string {
+	if seg.isIndex {
+		return "[" + strconv.Itoa(seg.index) + "]"
+	}
+	if seg.key == "" || strings.ContainsAny(seg.key, ".[]\\\"'") {
+		return `["` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(seg.key) + `"]`
+	}
+	if first {
+		return seg.key
+	}
+	return "." + seg.key
+}
+
+// Parent returns the map or array the data was read from or nil for the root
+func (m *Data) Parent() *Data {
+	if m == nil {
+		return nil
+	}
+	return m.up
+}
+
+// Root returns the data passed to CreateDataChain that the data was read from
+func (m *Data) Root() *Data {
+	if m == nil {
+		return nil
+	}
+	item := m
+	for item.up != nil {
+		item = item.up
+	}
+	return item
+}
+
+// Key returns the map key the data was read by
+// returns false if the data is the root or was read from an array
+func (m *Data) Key() (string, bool) {
+	if m == nil || m.at == nil || m.at.isIndex {
+		return "", false
+	}
+	return m.at.key, true
+}
+
+// Index returns the array index the data was read by
+// returns false if the data is the root or was read from a map
+func (m *Data) Index() (int, bool) {
+	if m == nil || m.at == nil || !m.at.isIndex {
+		return 0, false
+	}
+	return m.at.index, true
+}
@@ -0,0 +1,143 @@
+package go_data_chain
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGet(t *testing.T) {
+	var test_data interface{}
+
+	file, _ := ioutil.ReadFile("examples/example_data.yaml")
+	err := yaml.Unmarshal([]byte(file), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "map_string_1",
+			args: "data.maps.map_string_1",
+			want: "map_string_1",
+		},
+		{
+			name: "array_string_3",
+			args: "data.arrays[2]",
+			want: "array_string_3",
+		},
+		{
+			name: "quoted_key",
+			args: `data["maps"]['map_string_2']`,
+			want: "map_string_2",
+		},
+		{
+			name: "convert_int",
+			args: "data.convert_int.int_int",
+			want: "3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chain.Get(tt.args).ToString(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetEscapedKeys(t *testing.T) {
+	test_data := map[string]interface{}{
+		"key.with.dots": map[string]interface{}{
+			"a[0]": "brackets",
+		},
+		"items": []interface{}{
+			map[string]interface{}{"name": "first"},
+			map[string]interface{}{"name": "second"},
+		},
+	}
+	chain := CreateDataChain(test_data, false)
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "quoted",
+			args: `["key.with.dots"]["a[0]"]`,
+			want: "brackets",
+		},
+		{
+			name: "escaped",
+			args: `key\.with\.dots.a\[0\]`,
+			want: "brackets",
+		},
+		{
+			name: "index_then_key",
+			args: "items[1].name",
+			want: "second",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chain.Get(tt.args).ToString(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		wantErr bool
+	}{
+		{name: "empty", args: "", wantErr: false},
+		{name: "simple", args: "a.b[0]", wantErr: false},
+		{name: "leading_dot", args: ".a", wantErr: true},
+		{name: "double_dot", args: "a..b", wantErr: true},
+		{name: "negative_index", args: "a[-1]", wantErr: true},
+		{name: "unterminated_bracket", args: "a[0", wantErr: true},
+		{name: "unterminated_string", args: `a["b]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompilePath(tt.args); (err != nil) != tt.wantErr {
+				t.Errorf("CompilePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetSafe(t *testing.T) {
+	var test_data interface{}
+
+	file, _ := ioutil.ReadFile("examples/example_data.yaml")
+	err := yaml.Unmarshal([]byte(file), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, true)
+	p := MustCompilePath("data.maps_does_not_exist[0].name")
+	if got := chain.GetPath(p); got == nil || got.ToInterface() != nil {
+		t.Errorf("GetPath() = %v, want empty data", got)
+	}
+	if chain.Err == nil {
+		t.Errorf("GetPath() expected an error on the chain")
+	}
+	chain.Err = nil
+	chain.Get("data[")
+	if chain.Err == nil {
+		t.Errorf("Get() expected a parse error on the chain")
+	}
+	if got := CreateDataChain(test_data, false).Get("data.maps_does_not_exist"); got.Exists() {
+		t.Errorf("Get() = %v, want a missing item", got.ToInterface())
+	}
+}
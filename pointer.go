@@ -0,0 +1,116 @@
+package go_data_chain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePointer splits an RFC 6901 JSON Pointer into its reference tokens
+// - ptr: the pointer e.g. `/data/arrays/0`
+// returns the unescaped tokens, `~1` becomes `/` and `~0` becomes `~`
+func ParsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return []string{}, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("invalid pointer `%s`: must start with `/`", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, token := range tokens {
+		//check the escape sequences are valid
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 >= len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid pointer `%s`: bad escape in `%s`", ptr, token)
+			}
+		}
+		tokens[i] = unescapePointerToken(token)
+	}
+	return tokens, nil
+}
+
+// FormatPointer joins reference tokens into an RFC 6901 JSON Pointer
+// - tokens: the unescaped tokens
+func FormatPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(escapePointerToken(token))
+	}
+	return sb.String()
+}
+
+// escapePointerToken escapes `~` and `/` in a reference token
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// unescapePointerToken reverses escapePointerToken
+func unescapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// parsePointerIndex converts a reference token to an array index
+// - token: the token to convert
+// - length: the length of the array, returned for the `-` token
+func parsePointerIndex(token string, length int) (int, error) {
+	if token == "-" {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index `%s`", token)
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid array index `%s`", token)
+		}
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid array index `%s`", token)
+	}
+	return index, nil
+}
+
+// Pointer returns the item referenced by an RFC 6901 JSON Pointer
+// - ptr: the pointer e.g. `/data/arrays/0`, the empty pointer refers to the data itself
+// returns a Data object for the pointer, if the item does not exist or the pointer is invalid the Data object is missing
+func (m *Data) Pointer(ptr string) *Data {
+	tokens, err := ParsePointer(ptr)
+	if err != nil {
+		m.addError(err)
+		return m.missingItem()
+	}
+	current := m
+	for _, token := range tokens {
+		if items, ok := asArray(current.ToInterface()); ok {
+			index, err := parsePointerIndex(token, len(items))
+			if err != nil {
+				current.addError(err)
+				return current.missingItem()
+			}
+			current = current.GetArrayItem(index)
+		} else {
+			current = current.GetMapItem(token)
+		}
+	}
+	return current
+}
+
+// JSONPointer returns the RFC 6901 JSON Pointer of the data
+// relative to the value passed to CreateDataChain
+func (m *Data) JSONPointer() string {
+	var tokens []string
+	for item := m; item != nil && item.at != nil; item = item.up {
+		if item.at.isIndex {
+			tokens = append(tokens, strconv.Itoa(item.at.index))
+		} else {
+			tokens = append(tokens, item.at.key)
+		}
+	}
+	//reverse the tokens so they run from the root
+	for i, j := 0, len(tokens)-1; i < j; i, j = i+1, j-1 {
+		tokens[i], tokens[j] = tokens[j], tokens[i]
+	}
+	return FormatPointer(tokens)
+}
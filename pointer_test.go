@@ -0,0 +1,108 @@
+package go_data_chain
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestPointer(t *testing.T) {
+	var test_data interface{}
+
+	file, _ := ioutil.ReadFile("examples/example_data.yaml")
+	err := yaml.Unmarshal([]byte(file), &test_data)
+	if err != nil {
+		t.Error(err)
+	}
+	chain := CreateDataChain(test_data, false)
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "array_item",
+			args: "/data/arrays/0",
+			want: "array_string_1",
+		},
+		{
+			name: "map_item",
+			args: "/data/maps/map_string_2",
+			want: "map_string_2",
+		},
+		{
+			name: "convert_float",
+			args: "/data/convert_float/string_float",
+			want: "1.56",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chain.Pointer(tt.args)
+			if !reflect.DeepEqual(got.ToString(), tt.want) {
+				t.Errorf("Pointer() = %v, want %v", got.ToString(), tt.want)
+			}
+			if got.JSONPointer() != tt.args {
+				t.Errorf("JSONPointer() = %v, want %v", got.JSONPointer(), tt.args)
+			}
+		})
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "root", args: "", want: []string{}},
+		{name: "escaped", args: "/a~1b/m~0n", want: []string{"a/b", "m~n"}},
+		{name: "empty_token", args: "/", want: []string{""}},
+		{name: "no_slash", args: "a/b", wantErr: true},
+		{name: "bad_escape", args: "/a~2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePointer(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePointer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePointer() = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr && FormatPointer(got) != tt.args {
+				t.Errorf("FormatPointer() = %v, want %v", FormatPointer(got), tt.args)
+			}
+		})
+	}
+}
+
+func TestPointerSafe(t *testing.T) {
+	test_data := map[string]interface{}{
+		"a/b":   "slash",
+		"items": []interface{}{"one", "two"},
+	}
+	chain := CreateDataChain(test_data, true)
+	if got := chain.Pointer("/a~1b").ToString(); got != "slash" {
+		t.Errorf("Pointer() = %v, want slash", got)
+	}
+	tests := []string{"/items/-", "/items/01", "/items/5", "/missing/key", "bad"}
+	for _, ptr := range tests {
+		t.Run(ptr, func(t *testing.T) {
+			chain.Err = nil
+			if got := chain.Pointer(ptr); got == nil || got.ToInterface() != nil {
+				t.Errorf("Pointer() = %v, want empty data", got)
+			}
+			if chain.Err == nil {
+				t.Errorf("Pointer() expected an error on the chain")
+			}
+		})
+	}
+	if got := CreateDataChain(test_data, false).Pointer("/items/-"); got.Exists() {
+		t.Errorf("Pointer() = %v, want a missing item", got.ToInterface())
+	}
+}
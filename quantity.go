@@ -0,0 +1,138 @@
+package go_data_chain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Units maps the suffix of a quantity to the number of base units it stands for
+// an empty suffix is the base unit, add entries to a copy to support more suffixes
+type Units map[string]float64
+
+// ByteUnits are byte sizes with SI suffixes (kB, MB, GB ... powers of 1000)
+// and IEC suffixes (KiB, MiB, GiB ... powers of 1024), the base unit is a byte
+var ByteUnits = Units{
+	"": 1, "B": 1,
+	"k": 1e3, "K": 1e3, "kB": 1e3, "KB": 1e3, "Ki": 1 << 10, "KiB": 1 << 10,
+	"M": 1e6, "MB": 1e6, "Mi": 1 << 20, "MiB": 1 << 20,
+	"G": 1e9, "GB": 1e9, "Gi": 1 << 30, "GiB": 1 << 30,
+	"T": 1e12, "TB": 1e12, "Ti": 1 << 40, "TiB": 1 << 40,
+	"P": 1e15, "PB": 1e15, "Pi": 1 << 50, "PiB": 1 << 50,
+	"E": 1e18, "EB": 1e18, "Ei": 1 << 60, "EiB": 1 << 60,
+}
+
+// TimeUnits are time spans, the base unit is a second
+var TimeUnits = Units{
+	"": 1, "ns": 1e-9, "us": 1e-6, "µs": 1e-6, "ms": 1e-3, "s": 1,
+	"m": 60, "min": 60, "h": 3600, "d": 86400, "w": 604800,
+}
+
+// PercentUnits are percentages, the base unit is a fraction so "75%" is 0.75
+var PercentUnits = Units{
+	"": 1, "%": 0.01,
+}
+
+// With returns a copy of the units with more suffixes added
+// - units: the suffixes to add
+func (u Units) With(units Units) Units {
+	out := make(Units, len(u)+len(units))
+	for k, v := range u {
+		out[k] = v
+	}
+	for k, v := range units {
+		out[k] = v
+	}
+	return out
+}
+
+// factor returns the number of base units for a suffix
+// an exact match is used first, then a match ignoring case
+func (u Units) factor(suffix string) (float64, bool) {
+	if f, ok := u[suffix]; ok {
+		return f, true
+	}
+	for k, f := range u {
+		if strings.EqualFold(k, suffix) {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// ToUnit returns the data as a number of base units, a value that can not be converted returns 0
+// - units: the suffixes the value may have
+func (m *Data) ToUnit(units Units) float64 {
+	v, err := m.ToUnitE(units)
+	m.addError(err)
+	return v
+}
+
+// ToUnitE returns the data as a number of base units
+// strings are a number followed by an optional suffix from the units such as "1.5GB" or "250 ms",
+// other values are converted with ToFloat64E and are already in base units
+// - units: the suffixes the value may have
+func (m *Data) ToUnitE(units Units) (float64, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	s, ok := m.value.(string)
+	if !ok {
+		return m.ToFloat64E()
+	}
+	s = strings.TrimSpace(s)
+	i := len(s)
+	for i > 0 {
+		c := rune(s[i-1])
+		if unicode.IsDigit(c) || c == '.' || c == ' ' {
+			break
+		}
+		i--
+	}
+	number, suffix := strings.TrimSpace(s[:i]), s[i:]
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, m.conversionError("quantity", err)
+	}
+	factor, ok := units.factor(suffix)
+	if !ok {
+		return 0, m.conversionError("quantity", fmt.Errorf("unknown unit `%s`", suffix))
+	}
+	return f * factor, nil
+}
+
+// ToByteSize returns the data as a number of bytes, a value that can not be converted returns 0
+func (m *Data) ToByteSize() uint64 {
+	v, err := m.ToByteSizeE()
+	m.addError(err)
+	return v
+}
+
+// ToByteSizeE returns the data as a number of bytes
+// strings can have SI suffixes such as "1.5GB" or IEC suffixes such as "512MiB", see ByteUnits
+func (m *Data) ToByteSizeE() (uint64, error) {
+	f, err := m.ToUnitE(ByteUnits)
+	if err != nil {
+		return 0, err
+	}
+	f = math.Round(f)
+	if math.IsNaN(f) || f < 0 || f >= 0x1p64 {
+		return 0, m.conversionError("byte size", ErrOverflow)
+	}
+	return uint64(f), nil
+}
+
+// ToPercent returns the data as a fraction, a value that can not be converted returns 0
+func (m *Data) ToPercent() float64 {
+	v, err := m.ToPercentE()
+	m.addError(err)
+	return v
+}
+
+// ToPercentE returns the data as a fraction
+// "75%" and 0.75 both return 0.75
+func (m *Data) ToPercentE() (float64, error) {
+	return m.ToUnitE(PercentUnits)
+}
@@ -0,0 +1,66 @@
+package go_data_chain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToByteSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    uint64
+		wantErr bool
+	}{
+		{name: "iec", value: "512MiB", want: 512 << 20},
+		{name: "si_fraction", value: "1.5GB", want: 1500000000},
+		{name: "space", value: "2 KiB", want: 2048},
+		{name: "kubernetes", value: "256Mi", want: 256 << 20},
+		{name: "lower_case", value: "10kb", want: 10000},
+		{name: "bytes", value: "100B", want: 100},
+		{name: "number", value: 4096, want: 4096},
+		{name: "numeric_string", value: "4096", want: 4096},
+		{name: "unknown_unit", value: "10XB", wantErr: true},
+		{name: "negative", value: "-1KB", wantErr: true},
+		{name: "not_a_number", value: "lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateDataChain(tt.value, false).ToByteSizeE()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToByteSizeE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToByteSizeE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToPercentAndUnits(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		units Units
+		want  float64
+	}{
+		{name: "percent_string", value: "75%", units: PercentUnits, want: 0.75},
+		{name: "percent_fraction", value: 0.75, units: PercentUnits, want: 0.75},
+		{name: "percent_fraction_string", value: "0.5", units: PercentUnits, want: 0.5},
+		{name: "millis", value: "250ms", units: TimeUnits, want: 0.25},
+		{name: "hours", value: "2h", units: TimeUnits, want: 7200},
+		{name: "custom", value: "3 km", units: Units{"": 1, "m": 1, "km": 1000}, want: 3000},
+		{name: "extended", value: "2ft", units: Units{"m": 1}.With(Units{"ft": 0.3048}), want: 0.6096},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CreateDataChain(tt.value, false).ToUnit(tt.units); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ToUnit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	chain := CreateDataChain(map[string]interface{}{"ratio": "lots%"}, true)
+	if got := chain.GetMapItem("ratio").ToPercent(); got != 0 || chain.Err == nil {
+		t.Errorf("ToPercent() = %v, Err = %v, want 0 and an error", got, chain.Err)
+	}
+}
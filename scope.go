@@ -0,0 +1,31 @@
+package go_data_chain
+
+// Scope returns a copy of the data that collects its own errors
+// items read from the scope add their errors to the Err of the scope instead of the root,
+// the scope is always safe even if the chain was not created with safe set to true
+func (m *Data) Scope() *Data {
+	if m == nil {
+		m = m.missingItem()
+	}
+	c := *m
+	c.Err = nil
+	c.errSink = &c
+	return &c
+}
+
+// Try runs a function with a scope of the data, see Scope
+// - fn: the function that reads from the scope
+// returns the errors added while the function ran, the root is left untouched
+func (m *Data) Try(fn func(scope *Data)) error {
+	scope := m.Scope()
+	fn(scope)
+	return scope.Err
+}
+
+// Reset clears the errors collected by the root of a safe chain or the scope the data was read from
+func (m *Data) Reset() {
+	if m == nil || m.errSink == nil {
+		return
+	}
+	m.errSink.Err = nil
+}
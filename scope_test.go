@@ -0,0 +1,76 @@
+package go_data_chain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScope(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{
+		"data": map[string]interface{}{"name": "web", "items": []interface{}{1}},
+	}, true)
+	chain.GetMapItem("root_missing")
+
+	err := chain.GetMapItem("data").Try(func(scope *Data) {
+		if scope.GetMapItem("name").ToString() != "web" {
+			t.Errorf("ToString() = %v, want web", scope.GetMapItem("name").ToString())
+		}
+		scope.GetMapItem("other")
+		scope.Get("items[2]")
+	})
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Fatalf("Try() = %v, want the two errors from the scope", err)
+	}
+	var not_found *KeyNotFoundError
+	if !errors.As(err, &not_found) || not_found.Path != "/data/other" {
+		t.Errorf("errors.As() KeyNotFoundError = %v, want /data/other", not_found)
+	}
+	if !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Try() = %v, want an index out of range error", err)
+	}
+	if !errors.As(chain.Err, &multi) || len(multi.Errors) != 1 {
+		t.Errorf("Err = %v, want only the error added to the root", chain.Err)
+	}
+
+	if err := chain.Try(func(scope *Data) { scope.Get("data.name") }); err != nil {
+		t.Errorf("Try() = %v, want nil", err)
+	}
+
+	//a scope of a chain that is not safe still collects errors
+	unsafe := CreateDataChain(map[string]interface{}{}, false)
+	if err := unsafe.Try(func(scope *Data) { scope.GetMapItem("a") }); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Try() = %v, want a key not found error", err)
+	}
+	if unsafe.Err != nil {
+		t.Errorf("Err = %v, want nil", unsafe.Err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{"data": map[string]interface{}{}}, true)
+	item := chain.GetMapItem("data")
+	item.GetMapItem("a")
+	if chain.Err == nil {
+		t.Fatalf("Err = nil, want an error")
+	}
+	item.Reset()
+	if chain.Err != nil {
+		t.Errorf("Err = %v, want nil after Reset", chain.Err)
+	}
+	item.GetMapItem("b")
+	var multi *MultiError
+	if !errors.As(chain.Err, &multi) || len(multi.Errors) != 1 {
+		t.Errorf("Err = %v, want only the error added after Reset", chain.Err)
+	}
+
+	scope := chain.Scope()
+	scope.GetMapItem("c")
+	scope.GetMapItem("data").Reset()
+	if scope.Err != nil || chain.Err == nil {
+		t.Errorf("Reset() of a scope should only clear the scope")
+	}
+	var nil_data *Data
+	nil_data.Reset()
+	CreateDataChain(nil, false).Reset()
+}
@@ -0,0 +1,207 @@
+package go_data_chain
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeLayouts are the layouts ToTime tries, in order, when no layouts are given
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+}
+
+// isoDuration matches an ISO 8601 duration such as P1DT2H30M or PT0.5S
+var isoDuration = regexp.MustCompile(`^([-+])?P(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// ToTime returns the data as a time.Time, a value that can not be converted returns the zero time
+// - layouts: the layouts to parse strings with, TimeLayouts are used if none are given
+func (m *Data) ToTime(layouts ...string) time.Time {
+	v, err := m.ToTimeE(layouts...)
+	m.addError(err)
+	return v
+}
+
+// ToTimeE returns the data as a time.Time
+// strings are parsed with the layouts, numbers and numeric strings are Unix timestamps,
+// values from 1e12 are read as milliseconds, from 1e15 as microseconds and from 1e18 as nanoseconds
+// - layouts: the layouts to parse strings with, TimeLayouts are used if none are given
+func (m *Data) ToTimeE(layouts ...string) (time.Time, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	switch val := m.value.(type) {
+	case nil:
+		return time.Time{}, m.conversionError("time.Time", fmt.Errorf("no value"))
+	case time.Time:
+		return val, nil
+	case *time.Time:
+		if val != nil {
+			return *val, nil
+		}
+		return time.Time{}, m.conversionError("time.Time", fmt.Errorf("no value"))
+	}
+	if s, ok := m.value.(string); ok {
+		if len(layouts) == 0 {
+			layouts = TimeLayouts
+		}
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return time.Time{}, m.conversionError("time.Time", fmt.Errorf("does not match any layout"))
+		}
+	}
+	f, err := m.ToFloat64E()
+	if err != nil {
+		return time.Time{}, m.conversionError("time.Time", fmt.Errorf("unsupported type"))
+	}
+	if i, err := m.ToInt64E(); err == nil && float64(i) == f {
+		//integers keep every digit
+		return unixTime(i), nil
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return time.Time{}, m.conversionError("time.Time", ErrNotFinite)
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+}
+
+// unixTime converts a Unix timestamp guessing the unit from its size
+func unixTime(i int64) time.Time {
+	abs := i
+	if abs < 0 {
+		abs = -abs
+	}
+	switch {
+	case abs >= 1e18:
+		return time.Unix(0, i).UTC()
+	case abs >= 1e15:
+		return time.UnixMicro(i).UTC()
+	case abs >= 1e12:
+		return time.UnixMilli(i).UTC()
+	}
+	return time.Unix(i, 0).UTC()
+}
+
+// ToUnixTime returns the data as a Unix timestamp, a value that can not be converted returns 0
+// - unit: the unit of the timestamp, e.g. time.Second or time.Millisecond
+func (m *Data) ToUnixTime(unit time.Duration) int64 {
+	v, err := m.ToUnixTimeE(unit)
+	m.addError(err)
+	return v
+}
+
+// ToUnixTimeE returns the data as a Unix timestamp
+// the value is read with ToTimeE
+// - unit: the unit of the timestamp, e.g. time.Second or time.Millisecond
+func (m *Data) ToUnixTimeE(unit time.Duration) (int64, error) {
+	if unit <= 0 {
+		return 0, m.conversionError("unix time", fmt.Errorf("invalid unit `%v`", unit))
+	}
+	t, err := m.ToTimeE()
+	if err != nil {
+		return 0, err
+	}
+	if unit >= time.Second {
+		return t.Unix() / int64(unit/time.Second), nil
+	}
+	return t.Unix()*int64(time.Second/unit) + int64(t.Nanosecond())/int64(unit), nil
+}
+
+// WithDurationUnit returns a copy of the data that reads plain numbers as durations in the unit
+// - unit: the unit, the default is time.Second
+func (m *Data) WithDurationUnit(unit time.Duration) *Data {
+	return m.withOptions(func(o *options) {
+		o.durationUnit = unit
+	})
+}
+
+// ToDuration returns the data as a time.Duration, a value that can not be converted returns 0
+func (m *Data) ToDuration() time.Duration {
+	v, err := m.ToDurationE()
+	m.addError(err)
+	return v
+}
+
+// ToDurationE returns the data as a time.Duration
+// strings can be go durations such as "5m30s" or ISO 8601 durations such as "PT5M30S",
+// plain numbers and numeric strings are in the unit set by WithDurationUnit, seconds by default
+func (m *Data) ToDurationE() (time.Duration, error) {
+	if m == nil {
+		m = m.missingItem()
+	}
+	switch val := m.value.(type) {
+	case nil:
+		return 0, m.conversionError("time.Duration", fmt.Errorf("no value"))
+	case time.Duration:
+		return val, nil
+	case string:
+		if d, err := time.ParseDuration(val); err == nil {
+			return d, nil
+		}
+		if d, ok := parseISODuration(val); ok {
+			return d, nil
+		}
+		if _, err := strconv.ParseFloat(val, 64); err != nil {
+			return 0, m.conversionError("time.Duration", fmt.Errorf("not a go or ISO 8601 duration"))
+		}
+	}
+	f, err := m.ToFloat64E()
+	if err != nil {
+		return 0, m.conversionError("time.Duration", fmt.Errorf("unsupported type"))
+	}
+	unit := m.opts.durationUnit
+	if unit == 0 {
+		unit = time.Second
+	}
+	d := f * float64(unit)
+	if math.IsNaN(d) || math.Abs(d) >= math.MaxInt64 {
+		return 0, m.conversionError("time.Duration", ErrOverflow)
+	}
+	return time.Duration(d), nil
+}
+
+// parseISODuration parses an ISO 8601 duration made of weeks, days, hours, minutes and seconds
+// years and months have no fixed length so they are not supported
+func parseISODuration(s string) (time.Duration, bool) {
+	parts := isoDuration.FindStringSubmatch(s)
+	if parts == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, false
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total float64
+	found := false
+	for i, unit := range units {
+		if parts[i+2] == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(strings.Replace(parts[i+2], ",", ".", 1), 64)
+		if err != nil {
+			return 0, false
+		}
+		total += f * float64(unit)
+		found = true
+	}
+	if !found || total >= math.MaxInt64 {
+		return 0, false
+	}
+	if parts[1] == "-" {
+		total = -total
+	}
+	return time.Duration(total), true
+}
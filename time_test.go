@@ -0,0 +1,107 @@
+package go_data_chain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToTime(t *testing.T) {
+	want := time.Date(2023, 2, 24, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		value   interface{}
+		layouts []string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "rfc3339", value: "2023-02-24T10:30:00Z", want: want},
+		{name: "rfc3339_offset", value: "2023-02-24T11:30:00+01:00", want: want},
+		{name: "date_only", value: "2023-02-24", want: time.Date(2023, 2, 24, 0, 0, 0, 0, time.UTC)},
+		{name: "unix_seconds", value: 1677234600, want: want},
+		{name: "unix_millis", value: int64(1677234600000), want: want},
+		{name: "unix_string", value: "1677234600", want: want},
+		{name: "fractional_seconds", value: 1677234600.5, want: want.Add(500 * time.Millisecond)},
+		{name: "custom_layout", value: "24/02/2023 10:30", layouts: []string{"02/01/2006 15:04"}, want: want},
+		{name: "time_value", value: want, want: want},
+		{name: "invalid", value: "yesterday", wantErr: true},
+		{name: "map", value: map[string]interface{}{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateDataChain(tt.value, false).ToTimeE(tt.layouts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToTimeE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ToTimeE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	chain := CreateDataChain(map[string]interface{}{"created": "2023-02-24T10:30:00.123Z"}, false)
+	if got := chain.GetMapItem("created").ToUnixTime(time.Millisecond); got != 1677234600123 {
+		t.Errorf("ToUnixTime() = %v, want 1677234600123", got)
+	}
+	if got := chain.GetMapItem("created").ToUnixTime(time.Second); got != 1677234600 {
+		t.Errorf("ToUnixTime() = %v, want 1677234600", got)
+	}
+}
+
+func TestToDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		unit    time.Duration
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "go_duration", value: "5m30s", want: 5*time.Minute + 30*time.Second},
+		{name: "iso_8601", value: "PT5M30S", want: 5*time.Minute + 30*time.Second},
+		{name: "iso_8601_days", value: "P1DT2H", want: 26 * time.Hour},
+		{name: "iso_8601_fraction", value: "PT0.5S", want: 500 * time.Millisecond},
+		{name: "iso_8601_negative", value: "-PT1M", want: -time.Minute},
+		{name: "seconds_default", value: 90, want: 90 * time.Second},
+		{name: "numeric_string", value: "1.5", want: 1500 * time.Millisecond},
+		{name: "millis_unit", value: 250, unit: time.Millisecond, want: 250 * time.Millisecond},
+		{name: "invalid", value: "soon", wantErr: true},
+		{name: "empty_iso", value: "PT", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := CreateDataChain(tt.value, false)
+			if tt.unit != 0 {
+				chain = chain.WithDurationUnit(tt.unit)
+			}
+			got, err := chain.ToDurationE()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToDurationE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToDurationE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeSafeMode(t *testing.T) {
+	chain := CreateDataChain(map[string]interface{}{"timeout": "soon", "at": "never"}, true)
+	if got := chain.GetMapItem("timeout").ToDuration(); got != 0 {
+		t.Errorf("ToDuration() = %v, want 0", got)
+	}
+	if got := chain.GetMapItem("at").ToTime(); !got.IsZero() {
+		t.Errorf("ToTime() = %v, want the zero time", got)
+	}
+	if chain.Err == nil || !strings.Contains(chain.Err.Error(), "/timeout") || !strings.Contains(chain.Err.Error(), "/at") {
+		t.Errorf("Err = %v, want errors for /timeout and /at", chain.Err)
+	}
+
+	var target struct {
+		Timeout time.Duration `chain:"timeout"`
+		At      time.Time     `chain:"at"`
+	}
+	data := CreateDataChain(map[string]interface{}{"timeout": "PT1M", "at": "2023-02-24"}, false)
+	if err := data.Decode(&target); err != nil || target.Timeout != time.Minute || target.At.Year() != 2023 {
+		t.Errorf("Decode() = %+v, %v, want a minute and 2023", target, err)
+	}
+}